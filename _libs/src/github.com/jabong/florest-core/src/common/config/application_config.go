@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/jabong/florest-core/src/common/monitor"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/common/tracer"
 	"github.com/jabong/florest-core/src/common/utils/http"
 )
 
@@ -13,6 +14,7 @@ type AppConfig struct {
 	ServerPort           string
 	LogConfFile          string
 	MonitorConfig        monitor.MConf
+	TracerConfig         tracer.TConf
 	Performance          PerformanceConfigs
 	DynamicConfig        DynamicConfigInfo
 	HTTPConfig           http.Config `json:"HttpConfig"`
@@ -54,6 +54,9 @@ const (
 	//RequestValidationFailedCode is the error code if request validation fails
 	RequestValidationFailedCode = 1405
 
+	// RequestCancelledErrorCode is the error code if the client went away before the request was served
+	RequestCancelledErrorCode APPErrorCode = 1406
+
 	// InvalidURLKeyErrorCode is the error code if url contains an invalid key
 	ResourceErrorCode APPErrorCode = 1501
 
@@ -65,6 +68,9 @@ const (
 	// CacheErrorCode is the error code if any cache related error occurs
 	CacheErrorCode APPErrorCode = 1504
 
+	// NodeTimeoutErrorCode is the error code if a workflow node did not finish within its timeout
+	NodeTimeoutErrorCode APPErrorCode = 1505
+
 	InvalidRequestURI APPErrorCode = 1601
 
 	InvalidErrorCode = 2501
@@ -81,6 +87,8 @@ const (
 	HTTPFatalErrorCode                HTTPCode = 501
 	HTTPStatusNotFound                HTTPCode = 404
 	HTTPRateLimitExceeded             HTTPCode = 429
+	HTTPClientClosedRequest           HTTPCode = 499
+	HTTPStatusGatewayTimeout          HTTPCode = 504
 )
 
 var appErrorCodeToHTTPCodeMap = map[APPErrorCode]HTTPCode{
@@ -90,6 +98,7 @@ var appErrorCodeToHTTPCodeMap = map[APPErrorCode]HTTPCode{
 	IndexErrorCode:           HTTPStatusInternalServerErrorCode,
 	CacheErrorCode:           HTTPStatusInternalServerErrorCode,
 	RateLimiterInternalError: HTTPStatusInternalServerErrorCode,
+	NodeTimeoutErrorCode:     HTTPStatusGatewayTimeout,
 
 	ParamsInSufficientErrorCode: HTTPStatusBadRequestCode,
 	ParamsInValidErrorCode:      HTTPStatusBadRequestCode,
@@ -97,6 +106,7 @@ var appErrorCodeToHTTPCodeMap = map[APPErrorCode]HTTPCode{
 	InvalidURLKeyErrorCode:      HTTPStatusBadRequestCode,
 	RequestValidationFailedCode: HTTPStatusBadRequestCode,
 	InvalidRequestURI:           HTTPStatusNotFound,
+	RequestCancelledErrorCode:   HTTPClientClosedRequest,
 
 	InvalidErrorCode: HTTPFatalErrorCode,
 
@@ -20,6 +20,10 @@ const (
 	ResponseStatus        = "RESPONSE_STATUS"
 	ResponseHeadersConfig = "RESPONSE_HEADERS_CONFIG"
 	APIResponse           = "API_RESPONSE"
+	// ResponseHeaders holds the headers, a map[string]string, which the api workflow adds to the response
+	ResponseHeaders = "RESPONSE_HEADERS"
+	// ResponseHTTPStatus holds the http status, a HTTPCode, which the api workflow sets on a successful response
+	ResponseHTTPStatus = "RESPONSE_HTTP_STATUS"
 
 	APPError = "APPERROR"
 
@@ -35,6 +39,9 @@ const (
 
 	Result = "RESULT"
 
+	// NodeTimings holds the duration and outcome of every workflow node run for the request
+	NodeTimings = "NODE_TIMINGS"
+
 	UserAgent    = "USER_AGENT"
 	HTTPReferrer = "HTTP_REFERRER"
 	BucketsList  = "BUCKETSLIST"
@@ -0,0 +1,40 @@
+package formatter
+
+import (
+	"sync"
+
+	"github.com/jabong/florest-core/src/common/logger/message"
+)
+
+// Hook changes a log message before it is formatted, e.g. to mask the personal data in it
+type Hook func(msg *message.LogMsg)
+
+var (
+	hookMutex sync.RWMutex
+	hooks     []Hook
+)
+
+// AddHook adds a hook run on every log message before it is formatted, the hooks run in the order added
+func AddHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+	hookMutex.Lock()
+	defer hookMutex.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// applyHooks returns a copy of msg changed by the hooks, msg itself when there are none
+func applyHooks(msg *message.LogMsg) *message.LogMsg {
+	hookMutex.RLock()
+	defer hookMutex.RUnlock()
+	if len(hooks) == 0 || msg == nil {
+		return msg
+	}
+	res := *msg
+	res.StackTraces = append([]string(nil), msg.StackTraces...)
+	for _, hook := range hooks {
+		hook(&res)
+	}
+	return &res
+}
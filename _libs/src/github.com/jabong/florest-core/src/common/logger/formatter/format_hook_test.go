@@ -0,0 +1,34 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jabong/florest-core/src/common/logger/message"
+)
+
+func TestHooksAppliedBeforeFormatting(t *testing.T) {
+	defer func() { hooks = nil }()
+	AddHook(func(msg *message.LogMsg) {
+		msg.Message = strings.Replace(msg.Message, "9876543210", "********10", -1)
+	})
+	AddHook(nil)
+	msg := &message.LogMsg{Level: "info", Message: "phone 9876543210", StackTraces: []string{"main.go(1)"}}
+	for _, ftype := range []string{STRING, JSON} {
+		format, _ := GetFormatter(ftype)
+		res, _ := format.GetFormattedLog(msg).(string)
+		if strings.Contains(res, "9876543210") || !strings.Contains(res, "phone ********10") {
+			t.Errorf("Expected the %s log masked, got %s", ftype, res)
+		}
+	}
+	if msg.Message != "phone 9876543210" {
+		t.Errorf("The message logged should not be changed, got %s", msg.Message)
+	}
+}
+
+func TestNoHooks(t *testing.T) {
+	msg := &message.LogMsg{Message: "message"}
+	if applyHooks(msg) != msg {
+		t.Errorf("The message should be formatted as is without hooks")
+	}
+}
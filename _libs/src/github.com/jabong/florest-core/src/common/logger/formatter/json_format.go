@@ -11,6 +11,7 @@ type jsonFormat struct {
 
 //GetFormattedLog returns formatted log
 func (jf *jsonFormat) GetFormattedLog(msg *message.LogMsg) interface{} {
+	msg = applyHooks(msg)
 	jMsg, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Sprintf("\nError In converting to json %+v\n", msg)
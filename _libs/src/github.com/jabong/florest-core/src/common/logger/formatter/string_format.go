@@ -13,6 +13,7 @@ var formatString = "[level : %s, message : %s, tId : %s, reqId : %s, appId : %s,
 
 //GetFormattedLog returns formatted log as a string interface
 func (sf *stringFormat) GetFormattedLog(msg *message.LogMsg) interface{} {
+	msg = applyHooks(msg)
 	return fmt.Sprintf(formatString, msg.Level,
 		msg.Message,
 		msg.TransactionID,
@@ -4,7 +4,8 @@
 // response time, number of hits, failure/success ratio, etc, for each api defined in
 // the application
 //
-// By default it provides an implementation for datadog.
+// By default it provides an implementation for datadog. The Prometheus implementation
+// keeps the metrics in memory and serves them on /metrics of the web server.
 //
 // Refer https://github.com/jabong/florest-core/wiki/Monitor for more
 package monitor
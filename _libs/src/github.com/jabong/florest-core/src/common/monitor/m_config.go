@@ -18,8 +18,8 @@ type MConf struct {
 	// AgentServer is the montoring server ip and port
 	AgentServer string
 
-	// Platform specifies monitoring platform that is being used. For now only
-	// Datadog is supported in agent mode
+	// Platform specifies monitoring platform that is being used, Datadog in agent mode
+	// (DatadogAgent) or Prometheus
 	Platform string
 
 	// Verbose option if set to true prints down some information for debugging purpose
@@ -30,4 +30,8 @@ type MConf struct {
 
 	// MetricsServer to send server stats, e.g mem usage, disk usage, etc.
 	MetricsServer string
+
+	// HistogramBuckets are the upper bounds of the Prometheus histogram buckets, DefaultHistogramBuckets
+	// if not set
+	HistogramBuckets []float64 `json:",omitempty"`
 }
@@ -4,10 +4,18 @@ import ()
 
 const (
 	DatadogAgent string = "DatadogAgent"
+	Prometheus   string = "Prometheus"
 )
 
 const DefaultMonitor string = DatadogAgent
 
+// MetricsPath is where the Prometheus metrics are served
+const MetricsPath string = "/metrics"
+
+// DefaultHistogramBuckets are the upper bounds of the histogram buckets when not configured, fitting
+// the profiler timings in milliseconds from 1ms to 10s
+var DefaultHistogramBuckets = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
 // Different types of log message tags if verbose option is enabled
 const (
 	errMsgTag   string = "ERROR"
@@ -14,6 +14,12 @@ func Get(conf *MConf) (MInterface, error) {
 			return nil, err
 		}
 		return datadogAgentClient, nil
+	case Prometheus:
+		prometheusClient, err := newPrometheusClient(conf)
+		if err != nil {
+			return nil, err
+		}
+		return prometheusClient, nil
 	}
 	return nil, fmt.Errorf("Unknown Monitor Type %s requested", conf.Platform)
 }
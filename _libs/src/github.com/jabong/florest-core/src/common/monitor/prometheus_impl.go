@@ -0,0 +1,301 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusClient keeps the metrics in memory and serves them in the Prometheus text format,
+// to be scraped on MetricsPath. Tags of the form <key:value> become the label key="value", a tag
+// without a value the label tag="<tag>". The sample rate is ignored, every metric is recorded
+type PrometheusClient struct {
+	conf     *MConf
+	buckets  []float64
+	mutex    sync.Mutex
+	families map[string]*metricFamily
+}
+
+// metricFamily is a metric with the series of each label set it was recorded with
+type metricFamily struct {
+	kind   string
+	series map[string]*metricSeries
+}
+
+// metricSeries is a metric with a label set. A counter and a gauge keep the value, a histogram the
+// count of each bucket, a set the unique values
+type metricSeries struct {
+	labels  []labelPair
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+	uniques map[string]bool
+}
+
+type labelPair struct {
+	name  string
+	value string
+}
+
+// Kinds of Prometheus metrics
+const (
+	counterKind   = "counter"
+	gaugeKind     = "gauge"
+	histogramKind = "histogram"
+)
+
+// Init initialises the metrics, the histogram buckets are the configured ones or DefaultHistogramBuckets
+func (p *PrometheusClient) Init(conf *MConf) error {
+	p.conf = conf
+	p.buckets = DefaultHistogramBuckets
+	if len(conf.HistogramBuckets) > 0 {
+		p.buckets = append([]float64(nil), conf.HistogramBuckets...)
+		sort.Float64s(p.buckets)
+	}
+	p.families = make(map[string]*metricFamily)
+	return nil
+}
+
+// Info counts an info event in the events counter
+func (p *PrometheusClient) Info(data *MData) error {
+	return p.event("info", data)
+}
+
+// Success counts a success event in the events counter
+func (p *PrometheusClient) Success(data *MData) error {
+	return p.event("success", data)
+}
+
+// Warning counts a warning event in the events counter
+func (p *PrometheusClient) Warning(data *MData) error {
+	return p.event("warning", data)
+}
+
+// Error counts an error event in the events counter
+func (p *PrometheusClient) Error(data *MData) error {
+	return p.event("error", data)
+}
+
+// Gauge sets the value of a gauge
+func (p *PrometheusClient) Gauge(name string, value float64, tags []string, rate float64) error {
+	return p.record(name, gaugeKind, tags, func(s *metricSeries) {
+		s.value = value
+	})
+}
+
+// Count adds value to a counter, named with the suffix _total
+func (p *PrometheusClient) Count(name string, value int64, tags []string, rate float64) error {
+	if value < 0 {
+		return fmt.Errorf("Counter %s can not be decreased by %d", name, value)
+	}
+	if !strings.HasSuffix(name, "_total") {
+		name = name + "_total"
+	}
+	return p.record(name, counterKind, tags, func(s *metricSeries) {
+		s.value += float64(value)
+	})
+}
+
+// Histogram observes a value in the buckets of a histogram
+func (p *PrometheusClient) Histogram(name string, value float64, tags []string, rate float64) error {
+	return p.record(name, histogramKind, tags, func(s *metricSeries) {
+		if s.buckets == nil {
+			s.buckets = make([]uint64, len(p.buckets))
+		}
+		for i, bound := range p.buckets {
+			if value <= bound {
+				s.buckets[i]++
+			}
+		}
+		s.sum += value
+		s.count++
+	})
+}
+
+// Set adds a value to a set, exported as a gauge of the number of unique values
+func (p *PrometheusClient) Set(name string, value string, tags []string, rate float64) error {
+	return p.record(name, gaugeKind, tags, func(s *metricSeries) {
+		if s.uniques == nil {
+			s.uniques = make(map[string]bool)
+		}
+		s.uniques[value] = true
+		s.value = float64(len(s.uniques))
+	})
+}
+
+// SendAppMetrics serves the metrics on MetricsPath of serverIP, next to the metrics served by the web server
+func (p *PrometheusClient) SendAppMetrics(serverIP string) (err error) {
+	defer recoverFromPanic(&err)
+	if !p.conf.Enabled {
+		return nil
+	}
+	sock, err := net.Listen("tcp", serverIP)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, p)
+	go func() {
+		logMsg(infoMsgtag, fmt.Sprintf("App Metrics available at %s%s", serverIP, MetricsPath), p.conf)
+		http.Serve(sock, mux)
+	}()
+	return nil
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (p *PrometheusClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(p.Export())
+}
+
+// Export renders the metrics in the Prometheus text format, ordered by name and labels
+func (p *PrometheusClient) Export() []byte {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var buf bytes.Buffer
+	names := make([]string, 0, len(p.families))
+	for name := range p.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		family := p.families[name]
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, family.kind)
+		keys := make([]string, 0, len(family.series))
+		for key := range family.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := family.series[key]
+			if family.kind != histogramKind {
+				fmt.Fprintf(&buf, "%s%s %s\n", name, formatLabels(s.labels), formatValue(s.value))
+				continue
+			}
+			for i, bound := range p.buckets {
+				le := append(append([]labelPair(nil), s.labels...), labelPair{"le", formatValue(bound)})
+				fmt.Fprintf(&buf, "%s_bucket%s %d\n", name, formatLabels(le), s.buckets[i])
+			}
+			inf := append(append([]labelPair(nil), s.labels...), labelPair{"le", "+Inf"})
+			fmt.Fprintf(&buf, "%s_bucket%s %d\n", name, formatLabels(inf), s.count)
+			fmt.Fprintf(&buf, "%s_sum%s %s\n", name, formatLabels(s.labels), formatValue(s.sum))
+			fmt.Fprintf(&buf, "%s_count%s %d\n", name, formatLabels(s.labels), s.count)
+		}
+	}
+	return buf.Bytes()
+}
+
+// event counts an event by alert type, the title and body are not labels as they are seldom repeated
+func (p *PrometheusClient) event(alertType string, data *MData) error {
+	tags := append(getTagsArray(data.Tags), "alert_type:"+alertType)
+	return p.record("events_total", counterKind, tags, func(s *metricSeries) {
+		s.value++
+	})
+}
+
+// record updates the series of a metric with the label set of the tags
+func (p *PrometheusClient) record(name string, kind string, tags []string, update func(s *metricSeries)) (err error) {
+	defer recoverFromPanic(&err)
+	if !p.conf.Enabled {
+		return nil
+	}
+	name = sanitizeMetricName(p.conf.APPName + "_" + name)
+	labels := getLabels(tags)
+	key := formatLabels(labels)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	family, found := p.families[name]
+	if !found {
+		family = &metricFamily{kind: kind, series: make(map[string]*metricSeries)}
+		p.families[name] = family
+	}
+	if family.kind != kind {
+		err = fmt.Errorf("Metric %s is a %s, not a %s", name, family.kind, kind)
+		logMsg(errMsgTag, err, p.conf)
+		return err
+	}
+	s, found := family.series[key]
+	if !found {
+		s = &metricSeries{labels: labels}
+		family.series[key] = s
+	}
+	update(s)
+	return nil
+}
+
+// getLabels maps the tags to labels sorted by name, the last of the tags with the same name wins
+func getLabels(tags []string) []labelPair {
+	values := make(map[string]string)
+	for _, tag := range tags {
+		name, value := "tag", tag
+		if i := strings.Index(tag, ":"); i > 0 {
+			name, value = tag[:i], tag[i+1:]
+		}
+		values[sanitizeLabelName(name)] = value
+	}
+	labels := make([]labelPair, 0, len(values))
+	for name, value := range values {
+		labels = append(labels, labelPair{name, value})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].name < labels[j].name
+	})
+	return labels
+}
+
+func formatLabels(labels []labelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(labels))
+	for i, label := range labels {
+		pairs[i] = label.name + `="` + escaper.Replace(label.value) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sanitizeMetricName replaces the characters a metric name can not have, like the '#' and '-' of the profiler keys
+func sanitizeMetricName(name string) string {
+	return sanitizeName(name, true)
+}
+
+// sanitizeLabelName replaces the characters a label name can not have, a label name has no ':'
+func sanitizeLabelName(name string) string {
+	return sanitizeName(name, false)
+}
+
+func sanitizeName(name string, colon bool) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || (c >= '0' && c <= '9' && i > 0) || (colon && c == ':')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
+
+// newPrometheusClient creates a new instance of PrometheusClient
+func newPrometheusClient(conf *MConf) (p *PrometheusClient, err error) {
+	p = new(PrometheusClient)
+	if err = p.Init(conf); err != nil {
+		logMsg(errMsgTag, err, conf)
+		return nil, err
+	}
+	return p, nil
+}
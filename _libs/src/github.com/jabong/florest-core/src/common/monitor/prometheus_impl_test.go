@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func getTestPrometheusClient(t *testing.T) *PrometheusClient {
+	c := getTestConfig()
+	c.Platform = Prometheus
+	c.HistogramBuckets = []float64{100, 10}
+	m, err := Get(c)
+	if err != nil {
+		t.Fatalf("Get PrometheusClient Failed %v", err)
+	}
+	p, ok := m.(*PrometheusClient)
+	if !ok {
+		t.Fatalf("Get returned %T for Prometheus", m)
+	}
+	return p
+}
+
+func TestPrometheusExport(t *testing.T) {
+	p := getTestPrometheusClient(t)
+	p.Count("request_count", 2, []string{"api:v1", "bucket:Old"}, 1)
+	p.Count("request_count", 1, []string{"bucket:Old", "api:v1"}, 0.5)
+	p.Gauge("pool-size", 3, nil, 1)
+	p.Gauge("pool-size", 5, nil, 1)
+	p.Histogram("AddressValidator#Execute", 5, []string{"AddressValidator#Execute"}, 1)
+	p.Histogram("AddressValidator#Execute", 50, []string{"AddressValidator#Execute"}, 1)
+	p.Set("visitors", "a", nil, 1)
+	p.Set("visitors", "a", nil, 1)
+	p.Set("visitors", "b", nil, 1)
+	p.Error(&MData{Title: "down", Tags: map[string]string{"env": "local"}})
+
+	expected := `# TYPE TestJadeGO_AddressValidator_Execute histogram
+TestJadeGO_AddressValidator_Execute_bucket{tag="AddressValidator#Execute",le="10"} 1
+TestJadeGO_AddressValidator_Execute_bucket{tag="AddressValidator#Execute",le="100"} 2
+TestJadeGO_AddressValidator_Execute_bucket{tag="AddressValidator#Execute",le="+Inf"} 2
+TestJadeGO_AddressValidator_Execute_sum{tag="AddressValidator#Execute"} 55
+TestJadeGO_AddressValidator_Execute_count{tag="AddressValidator#Execute"} 2
+# TYPE TestJadeGO_events_total counter
+TestJadeGO_events_total{alert_type="error",env="local"} 1
+# TYPE TestJadeGO_pool_size gauge
+TestJadeGO_pool_size 5
+# TYPE TestJadeGO_request_count_total counter
+TestJadeGO_request_count_total{api="v1",bucket="Old"} 3
+# TYPE TestJadeGO_visitors gauge
+TestJadeGO_visitors 2
+`
+	if got := string(p.Export()); got != expected {
+		t.Errorf("Exported\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestPrometheusKindMismatch(t *testing.T) {
+	p := getTestPrometheusClient(t)
+	if err := p.Gauge("latency", 1, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Histogram("latency", 1, nil, 1); err == nil {
+		t.Error("Histogram recorded on a gauge")
+	}
+	if err := p.Count("latency", -1, nil, 1); err == nil {
+		t.Error("Counter decreased")
+	}
+}
+
+func TestPrometheusServeHTTP(t *testing.T) {
+	p := getTestPrometheusClient(t)
+	p.Count("hits", 1, []string{`path:a"b`}, 1)
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", MetricsPath, nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content type %s", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `TestJadeGO_hits_total{path="a\"b"} 1`) {
+		t.Errorf("Metrics served %s", body)
+	}
+}
+
+func TestPrometheusDisabled(t *testing.T) {
+	c := getTestConfig()
+	c.Platform = Prometheus
+	c.Enabled = false
+	m, _ := Get(c)
+	m.Count("hits", 1, nil, 1)
+	if out := m.(*PrometheusClient).Export(); len(out) != 0 {
+		t.Errorf("Disabled monitor exported %s", out)
+	}
+}
@@ -0,0 +1,10 @@
+// Package tracer traces a request across the services it goes through.
+//
+// A request carrying a W3C trace context (the traceparent and tracestate headers) continues
+// the trace of its caller, else a new trace is started. Spans are started as children of the
+// span in a context.Context and put in the context returned, the trace context is injected
+// in the headers of the calls made to other services.
+//
+// Ended spans are exported in batches, as OTLP json, to a file or to an OTLP collector over http.
+// Nothing is recorded when tracing is not enabled, a nil *Span can be used like any other.
+package tracer
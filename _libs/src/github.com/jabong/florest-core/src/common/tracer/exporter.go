@@ -0,0 +1,267 @@
+package tracer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// exporter exports a batch of ended spans
+type exporter interface {
+	export(spans []*Span) error
+}
+
+// fileExporter appends every batch to a file as a line of OTLP json
+type fileExporter struct {
+	serviceName string
+	mutex       sync.Mutex
+	file        *os.File
+}
+
+func (e *fileExporter) export(spans []*Span) error {
+	body, err := json.Marshal(toOTLP(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	_, err = e.file.Write(append(body, '\n'))
+	return err
+}
+
+// otlpExporter posts every batch to an OTLP collector, over http as json
+type otlpExporter struct {
+	serviceName string
+	endpoint    string
+	client      *http.Client
+}
+
+func (e *otlpExporter) export(spans []*Span) error {
+	body, err := json.Marshal(toOTLP(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Collector %s responded with %s", e.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// newExporter creates the configured exporter
+func newExporter(conf *TConf) (exporter, error) {
+	switch conf.Exporter {
+	case File:
+		if conf.FilePath == "" {
+			return nil, fmt.Errorf("Tracer exporter %s needs a FilePath", File)
+		}
+		file, err := os.OpenFile(conf.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return &fileExporter{serviceName: conf.ServiceName, file: file}, nil
+	case OTLP:
+		if conf.Endpoint == "" {
+			return nil, fmt.Errorf("Tracer exporter %s needs an Endpoint", OTLP)
+		}
+		timeout := conf.Timeout
+		if timeout <= 0 {
+			timeout = DefaultTimeout
+		}
+		client := &http.Client{Timeout: time.Duration(timeout) * time.Millisecond}
+		return &otlpExporter{serviceName: conf.ServiceName, endpoint: conf.Endpoint, client: client}, nil
+	}
+	return nil, fmt.Errorf("Unknown tracer exporter %q, should be %s or %s", conf.Exporter, File, OTLP)
+}
+
+// batchProcessor queues the ended spans and exports them when a batch is full or the flush interval passes
+type batchProcessor struct {
+	exporter  exporter
+	batchSize int
+	queue     chan *Span
+	flushes   chan chan bool
+	done      chan bool
+}
+
+func newBatchProcessor(e exporter, batchSize int, flushInterval time.Duration) *batchProcessor {
+	p := &batchProcessor{
+		exporter:  e,
+		batchSize: batchSize,
+		queue:     make(chan *Span, maxQueueSize),
+		flushes:   make(chan chan bool),
+		done:      make(chan bool),
+	}
+	go p.run(flushInterval)
+	return p
+}
+
+// add queues an ended span, it is dropped if the queue is full so that a slow exporter never slows requests
+func (p *batchProcessor) add(span *Span) {
+	select {
+	case p.queue <- span:
+	default:
+	}
+}
+
+// flush exports the queued spans and waits for the export
+func (p *batchProcessor) flush() {
+	exported := make(chan bool)
+	select {
+	case p.flushes <- exported:
+		<-exported
+	case <-p.done:
+	}
+}
+
+// stop exports the queued spans and stops the processor
+func (p *batchProcessor) stop() {
+	p.flush()
+	close(p.done)
+}
+
+func (p *batchProcessor) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, p.batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.exporter.export(batch); err != nil {
+			// The tracer can not log with the logger, the logger config depends on it
+			fmt.Fprintf(os.Stderr, "Tracer export of %d spans failed - %v\n", len(batch), err)
+		}
+		batch = make([]*Span, 0, p.batchSize)
+	}
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case exported := <-p.flushes:
+			for queued := len(p.queue); queued > 0; queued-- {
+				batch = append(batch, <-p.queue)
+			}
+			export()
+			close(exported)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// OTLP json of a batch of spans, see opentelemetry-proto ExportTraceServiceRequest
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	TraceState        string          `json:"traceState,omitempty"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Span kinds and status codes of OTLP
+var otlpKinds = map[string]int{SpanKindInternal: 1, SpanKindServer: 2, SpanKindClient: 3}
+
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+func toOTLP(serviceName string, spans []*Span) otlpRequest {
+	otlpSpans := make([]otlpSpan, len(spans))
+	for i, s := range spans {
+		s.mutex.Lock()
+		otlpSpans[i] = otlpSpan{
+			TraceID:           s.context.TraceID,
+			SpanID:            s.context.SpanID,
+			TraceState:        s.context.TraceState,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              otlpKinds[s.kind],
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        toOTLPAttributes(s.attributes),
+			Status:            otlpStatus{Code: otlpStatusOk},
+		}
+		if s.failed {
+			otlpSpans[i].Status = otlpStatus{Code: otlpStatusError, Message: s.message}
+		}
+		s.mutex.Unlock()
+	}
+	resource := otlpResource{Attributes: toOTLPAttributes(map[string]string{"service.name": serviceName})}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   resource,
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "florest-core"}, Spans: otlpSpans}},
+	}}}
+}
+
+func toOTLPAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res := make([]otlpAttribute, len(keys))
+	for i, key := range keys {
+		res[i] = otlpAttribute{Key: key, Value: otlpValue{StringValue: attributes[key]}}
+	}
+	return res
+}
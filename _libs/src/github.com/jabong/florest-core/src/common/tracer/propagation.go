@@ -0,0 +1,70 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Extract returns ctx with the trace context of the headers of a request, the span started next
+// continues the trace of the caller. ctx is returned as is when the headers have no valid trace context
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, ok := ParseTraceParent(header.Get(TraceParentHeader))
+	if !ok {
+		return ctx
+	}
+	sc.TraceState = header.Get(TraceStateHeader)
+	return context.WithValue(ctx, remoteKey, sc)
+}
+
+// Inject sets the trace context of the span in ctx in the headers of a call to another service
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(TraceParentHeader, FormatTraceParent(sc))
+	if sc.TraceState != "" {
+		header.Set(TraceStateHeader, sc.TraceState)
+	}
+}
+
+// ParseTraceParent parses a traceparent header, version-traceid-parentid-flags. A version after 00
+// can have more fields, they are ignored
+func ParseTraceParent(value string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if !sc.IsValid() || !isHex(parts[3], 2) {
+		return SpanContext{}, false
+	}
+	var flags byte
+	fmt.Sscanf(parts[3], "%02x", &flags)
+	sc.Sampled = flags&1 == 1
+	return sc, true
+}
+
+// FormatTraceParent formats the span context as a version 00 traceparent header
+func FormatTraceParent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID + "-" + sc.SpanID + "-" + flags
+}
+
+func isHex(value string, n int) bool {
+	if len(value) != n {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package tracer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// SpanContext identifies a span within its trace, it is what is propagated to other services
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	Sampled    bool
+	TraceState string
+}
+
+// IsValid tells if the span context has a trace and a span id
+func (sc SpanContext) IsValid() bool {
+	return isValidID(sc.TraceID, 32) && isValidID(sc.SpanID, 16)
+}
+
+// Span is a timed operation of a trace, like serving a request or a call to a db
+type Span struct {
+	name     string
+	kind     string
+	context  SpanContext
+	parentID string
+	start    time.Time
+
+	mutex      sync.Mutex
+	end        time.Time
+	attributes map[string]string
+	failed     bool
+	message    string
+	ended      bool
+}
+
+// Context returns the span context of the span, an invalid one for a nil span
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.attributes == nil {
+		s.attributes = make(map[string]string)
+	}
+	s.attributes[key] = value
+}
+
+// SetError marks the span as failed with err, nothing is done for a nil err
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failed = true
+	s.message = err.Error()
+}
+
+// End ends the span, it is exported if its trace is sampled. Ending a span again does nothing
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mutex.Unlock()
+	if s.context.Sampled {
+		if p := getProcessor(); p != nil {
+			p.add(s)
+		}
+	}
+}
+
+// newID returns a random id of n bytes in hex, never all zeros
+func newID(n int) string {
+	b := make([]byte, n)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			b[0] = 1
+		}
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+// isValidID tells if id is n lowercase hex characters, not all zeros
+func isValidID(id string, n int) bool {
+	if len(id) != n {
+		return false
+	}
+	zeros := true
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+		if c != '0' {
+			zeros = false
+		}
+	}
+	return !zeros
+}
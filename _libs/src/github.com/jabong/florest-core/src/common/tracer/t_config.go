@@ -0,0 +1,34 @@
+package tracer
+
+// TConf is the configuration of the tracer
+type TConf struct {
+
+	// Enabled determines whether spans are recorded and exported
+	Enabled bool
+
+	// ServiceName names the service the spans are exported for, the app name if not set
+	ServiceName string
+
+	// Exporter is where the spans are exported, a file (File) or an OTLP collector over http (OTLP)
+	Exporter string
+
+	// FilePath is the file the File exporter appends the spans to, a line of OTLP json per batch
+	FilePath string
+
+	// Endpoint is the url the OTLP exporter posts the spans to, e.g. http://localhost:4318/v1/traces
+	Endpoint string
+
+	// SamplingRate is the fraction of the traces started by the service that are exported, all if
+	// not set. A trace continued from the caller is exported if the caller sampled it
+	SamplingRate float64
+
+	// BatchSize is the number of spans exported at once, DefaultBatchSize if not set
+	BatchSize int
+
+	// FlushInterval is the time in milliseconds an ended span waits at most to be exported,
+	// DefaultFlushInterval if not set
+	FlushInterval int
+
+	// Timeout is the time in milliseconds an export to the collector is allowed, DefaultTimeout if not set
+	Timeout int
+}
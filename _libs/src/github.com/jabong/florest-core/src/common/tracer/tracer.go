@@ -0,0 +1,124 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type contextKey int
+
+// Keys of the span and of the trace context of the caller in a context
+const (
+	spanKey contextKey = iota
+	remoteKey
+)
+
+var (
+	mutex        sync.RWMutex
+	processorObj *batchProcessor
+	samplingRate float64
+)
+
+// Initialize starts exporting the spans ended with the configured exporter. Nothing is traced when
+// tracing is not enabled
+func Initialize(conf *TConf) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if processorObj != nil {
+		processorObj.stop()
+		processorObj = nil
+	}
+	if conf == nil || !conf.Enabled {
+		return nil
+	}
+	if conf.SamplingRate < 0 || conf.SamplingRate > 1 {
+		return fmt.Errorf("Tracer sampling rate %v should be between 0 and 1", conf.SamplingRate)
+	}
+	exporter, err := newExporter(conf)
+	if err != nil {
+		return err
+	}
+	samplingRate = conf.SamplingRate
+	if samplingRate == 0 {
+		samplingRate = 1
+	}
+	batchSize, flushInterval := conf.BatchSize, conf.FlushInterval
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	processorObj = newBatchProcessor(exporter, batchSize, time.Duration(flushInterval)*time.Millisecond)
+	return nil
+}
+
+// Flush exports the spans ended so far
+func Flush() {
+	if p := getProcessor(); p != nil {
+		p.flush()
+	}
+}
+
+// IsEnabled tells if the spans are recorded
+func IsEnabled() bool {
+	return getProcessor() != nil
+}
+
+func getProcessor() *batchProcessor {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return processorObj
+}
+
+// StartSpan starts a span, a child of the span in ctx or of the trace context extracted from the
+// caller, else the first span of a new trace. The context returned has the span. The span is nil when
+// tracing is not enabled
+func StartSpan(ctx context.Context, name string, kind string) (context.Context, *Span) {
+	if !IsEnabled() {
+		return ctx, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{name: name, kind: kind, start: time.Now()}
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		span.context = SpanContext{TraceID: parent.TraceID, SpanID: newID(8), Sampled: parent.Sampled, TraceState: parent.TraceState}
+		span.parentID = parent.SpanID
+	} else {
+		span.context = SpanContext{TraceID: newID(16), SpanID: newID(8), Sampled: rand.Float64() < samplingRate}
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// ContextWithSpan returns ctx with the span, the spans started with it are its children
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey, span)
+}
+
+// SpanFromContext returns the span in ctx, nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the span context of the span in ctx, else the one extracted from the caller
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.context
+	}
+	sc, _ := ctx.Value(remoteKey).(SpanContext)
+	return sc
+}
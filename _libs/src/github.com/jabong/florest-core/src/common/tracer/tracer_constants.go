@@ -0,0 +1,29 @@
+package tracer
+
+// Exporters of the spans
+const (
+	File string = "File"
+	OTLP string = "OTLP"
+)
+
+// Headers carrying the W3C trace context
+const (
+	TraceParentHeader string = "traceparent"
+	TraceStateHeader  string = "tracestate"
+)
+
+// Kinds of span, a span for a request served, for a call made to another service, or for work done within
+const (
+	SpanKindServer   string = "server"
+	SpanKindClient   string = "client"
+	SpanKindInternal string = "internal"
+)
+
+// Defaults of the configuration
+const (
+	DefaultBatchSize     int = 512
+	DefaultFlushInterval int = 5000
+	DefaultTimeout       int = 10000
+	// maxQueueSize is the number of ended spans waiting to be exported, more are dropped
+	maxQueueSize int = 4096
+)
@@ -0,0 +1,165 @@
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	sc, ok := ParseTraceParent(testTraceParent)
+	if !ok || sc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Fatalf("Wrong span context %+v parsed from %s", sc, testTraceParent)
+	}
+	if res := FormatTraceParent(sc); res != testTraceParent {
+		t.Errorf("Expected traceparent %s, got %s", testTraceParent, res)
+	}
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+	for _, value := range invalid {
+		if _, ok := ParseTraceParent(value); ok {
+			t.Errorf("Traceparent %q should be invalid", value)
+		}
+	}
+	if _, ok := ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra"); !ok {
+		t.Errorf("A later version can have more fields")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	if err := Initialize(&TConf{Enabled: false}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, span := StartSpan(context.Background(), "test", SpanKindInternal)
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatalf("No span should be started when tracing is not enabled")
+	}
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.End()
+}
+
+func TestSpansExportedToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.json")
+	if err := Initialize(&TConf{Enabled: true, ServiceName: "test", Exporter: File, FilePath: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer Initialize(nil)
+
+	header := http.Header{}
+	header.Set(TraceParentHeader, testTraceParent)
+	header.Set(TraceStateHeader, "vendor=value")
+	ctx, server := StartSpan(Extract(context.Background(), header), "HTTP GET", SpanKindServer)
+	clientCtx, client := StartSpan(ctx, "HTTP GET", SpanKindClient)
+	client.SetError(errors.New("timeout"))
+	client.End()
+	server.SetAttribute("http.status_code", "200")
+	server.End()
+	server.End()
+
+	outbound := http.Header{}
+	Inject(clientCtx, outbound)
+	sc, ok := ParseTraceParent(outbound.Get(TraceParentHeader))
+	if !ok || sc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID != client.Context().SpanID {
+		t.Errorf("Expected the client span injected, got %q", outbound.Get(TraceParentHeader))
+	}
+	if outbound.Get(TraceStateHeader) != "vendor=value" {
+		t.Errorf("Expected the tracestate passed on, got %q", outbound.Get(TraceStateHeader))
+	}
+
+	Flush()
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatalf("Invalid OTLP json %s - %v", body, err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected the 2 spans exported once, got %+v", spans)
+	}
+	if spans[0].ParentSpanID != server.Context().SpanID || spans[0].Kind != 3 || spans[0].Status.Code != otlpStatusError {
+		t.Errorf("Wrong client span %+v", spans[0])
+	}
+	if spans[1].ParentSpanID != "00f067aa0ba902b7" || spans[1].Kind != 2 || spans[1].Attributes[0].Value.StringValue != "200" {
+		t.Errorf("Wrong server span %+v", spans[1])
+	}
+	if res := req.ResourceSpans[0].Resource.Attributes[0]; res.Key != "service.name" || res.Value.StringValue != "test" {
+		t.Errorf("Wrong resource %+v", res)
+	}
+}
+
+func TestSpansExportedToCollector(t *testing.T) {
+	var mutex sync.Mutex
+	var bodies []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		bodies = append(bodies, string(body))
+		mutex.Unlock()
+	}))
+	defer collector.Close()
+	if err := Initialize(&TConf{Enabled: true, Exporter: OTLP, Endpoint: collector.URL + "/v1/traces", BatchSize: 1}); err != nil {
+		t.Fatal(err)
+	}
+	defer Initialize(nil)
+
+	_, span := StartSpan(context.Background(), "root", SpanKindInternal)
+	if !span.Context().IsValid() || !span.Context().Sampled {
+		t.Fatalf("A new trace should be started and sampled, got %+v", span.Context())
+	}
+	span.End()
+	// A trace the caller did not sample is not exported
+	header := http.Header{}
+	header.Set(TraceParentHeader, strings.TrimSuffix(testTraceParent, "01")+"00")
+	_, unsampled := StartSpan(Extract(context.Background(), header), "unsampled", SpanKindServer)
+	unsampled.End()
+	Flush()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"name":"root"`) {
+		t.Errorf("Expected the sampled span posted to the collector, got %v", bodies)
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	confs := []*TConf{
+		{Enabled: true, Exporter: "Unknown"},
+		{Enabled: true, Exporter: File},
+		{Enabled: true, Exporter: OTLP},
+		{Enabled: true, Exporter: OTLP, Endpoint: "http://localhost", SamplingRate: 2},
+	}
+	for _, conf := range confs {
+		if err := Initialize(conf); err == nil {
+			t.Errorf("Expected an error for %+v", conf)
+		}
+	}
+	if IsEnabled() {
+		t.Errorf("Tracing should not be enabled by an invalid configuration")
+	}
+}
@@ -1,14 +1,17 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/tracer"
 )
 
 //Get makes an http get request with default header parameters
@@ -20,7 +23,32 @@ func Get(url string, headers map[string]string,
 	if err != nil {
 		return nil, err
 	}
-	return httpExecuter(req, resp, headers, timeOut)
+	return httpExecuter(context.Background(), req, resp, headers, timeOut)
+}
+
+//GetWithContext makes an http get request like Get, traced as a child of the span in ctx. The trace
+//context is passed on in the headers of the request
+func GetWithContext(ctx context.Context, url string, headers map[string]string,
+	timeOut time.Duration) (ret *APIResponse, err error) {
+	var resp *http.Response // response
+	defer recoverFromPanic("GetWithContext()", resp, &err)
+	req, err := getReqWithoutBody("GET", url)
+	if err != nil {
+		return nil, err
+	}
+	return httpExecuter(ctx, req, resp, headers, timeOut)
+}
+
+//PostWithContext makes an http post request like Post, traced as a child of the span in ctx
+func PostWithContext(ctx context.Context, url string, headers map[string]string, body string,
+	timeOut time.Duration) (ret *APIResponse, err error) {
+	var resp *http.Response // response
+	defer recoverFromPanic("PostWithContext()", resp, &err)
+	req, err := getReqWithBody("POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	return httpExecuter(ctx, req, resp, headers, timeOut)
 }
 
 //Post makes an http post request with given parameters
@@ -32,7 +60,7 @@ func Post(url string, headers map[string]string, body string,
 	if err != nil {
 		return nil, err
 	}
-	return httpExecuter(req, resp, headers, timeOut)
+	return httpExecuter(context.Background(), req, resp, headers, timeOut)
 }
 
 //Put makes an http put request with given parameters
@@ -44,7 +72,7 @@ func Put(url string, headers map[string]string, body string,
 	if err != nil {
 		return nil, err
 	}
-	return httpExecuter(req, resp, headers, timeOut)
+	return httpExecuter(context.Background(), req, resp, headers, timeOut)
 }
 
 //Delete makes an http delete request with given parameters
@@ -56,7 +84,7 @@ func Delete(url string, headers map[string]string, body string,
 	if err != nil {
 		return nil, err
 	}
-	return httpExecuter(req, resp, headers, timeOut)
+	return httpExecuter(context.Background(), req, resp, headers, timeOut)
 }
 
 //Patch makes an http patch request with given parameters
@@ -68,7 +96,7 @@ func Patch(url string, headers map[string]string, body string,
 	if err != nil {
 		return nil, err
 	}
-	return httpExecuter(req, resp, headers, timeOut)
+	return httpExecuter(context.Background(), req, resp, headers, timeOut)
 }
 
 // recoverFromPanic closes any open http response, recovers with panic details
@@ -81,8 +109,8 @@ func recoverFromPanic(name string, resp *http.Response, err *error) {
 	}
 }
 
-// httpExecuter executes the http call with given headers and timeout
-func httpExecuter(req *http.Request, resp *http.Response, headers map[string]string, timeOut time.Duration) (*APIResponse, error) {
+// httpExecuter executes the http call with given headers and timeout, traced as a child of the span in ctx
+func httpExecuter(ctx context.Context, req *http.Request, resp *http.Response, headers map[string]string, timeOut time.Duration) (*APIResponse, error) {
 	ret := new(APIResponse)
 	var err error
 
@@ -90,6 +118,20 @@ func httpExecuter(req *http.Request, resp *http.Response, headers map[string]str
 	for key, val := range headers {
 		req.Header.Add(key, val)
 	}
+	// The query is not recorded, it can have personal data
+	ctx, span := tracer.StartSpan(ctx, "HTTP "+req.Method, tracer.SpanKindClient)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+	tracer.Inject(ctx, req.Header)
+	if ctx != nil {
+		// The request is cancelled with ctx
+		req = req.WithContext(ctx)
+	}
+
 	var client *http.Client
 	// set client
 	if isPoolSet() {
@@ -113,6 +155,7 @@ func httpExecuter(req *http.Request, resp *http.Response, headers map[string]str
 
 	// read http status
 	ret.HTTPStatus = constants.HTTPCode(resp.StatusCode)
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
 
 	// read headers
 	ret.Headers = make(map[string]string)
@@ -123,6 +166,7 @@ func httpExecuter(req *http.Request, resp *http.Response, headers map[string]str
 	// read body
 	body, berr := ioutil.ReadAll(resp.Body)
 	if berr != nil {
+		err = berr
 		return nil, berr
 	}
 	ret.Body = body
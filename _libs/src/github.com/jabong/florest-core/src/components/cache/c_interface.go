@@ -25,6 +25,10 @@ type CInterface interface {
 	// which sets the timeout for the particular item, does not take expirySec from config
 	SetWithTimeout(item Item, serialize bool, compress bool, ttl int32) error
 
+	// SetIfNotExists sets the item into cache with the given timeout only if the key is not already present.
+	// It returns true if the item was set
+	SetIfNotExists(item Item, serialize bool, compress bool, ttl int32) (bool, error)
+
 	// Delete deletes a key from cache
 	Delete(key string) error
 
@@ -84,6 +84,15 @@ func (ra *RedisClientAdapter) SetWithTimeout(item Item, serialize bool, compress
 	return nil
 }
 
+func (ra *RedisClientAdapter) SetIfNotExists(item Item, serialize bool, compress bool, ttl int32) (bool, error) {
+	hashKey := ra.getHashKey(item.Key)
+	set, err := ra.client.SetNX(hashKey, item.Value, time.Duration(ttl)*time.Second).Result()
+	if err != nil {
+		return false, getErrObj(ErrSetFailure, "Setting key if not exists failed with error : "+err.Error())
+	}
+	return set, nil
+}
+
 func (ra *RedisClientAdapter) Delete(key string) error {
 	hashKey := ra.getHashKey(key)
 	val, err := ra.client.Del(hashKey).Result()
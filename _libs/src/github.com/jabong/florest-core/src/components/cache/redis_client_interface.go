@@ -8,6 +8,7 @@ import (
 type redisClientInterface interface {
 	Get(key string) *redis.StringCmd
 	Set(key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	SetNX(key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Del(keys ...string) *redis.IntCmd
 	MGet(keys ...string) *redis.SliceCmd
 	MSet(keys ...string) *redis.StatusCmd
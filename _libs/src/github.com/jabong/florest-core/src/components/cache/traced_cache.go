@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jabong/florest-core/src/common/tracer"
+)
+
+// tracedCache traces the calls to a cache as children of the span in its context. The calls are not
+// made once the context is done, a call in progress is not cancelled
+type tracedCache struct {
+	CInterface
+	ctx context.Context
+	key string
+}
+
+// GetWithContext returns the cache interface for given key, its calls are traced as children of the span in ctx
+// and fail with the error of ctx once it is done
+func GetWithContext(ctx context.Context, key string) (CInterface, error) {
+	c, err := Get(key)
+	if err != nil || ctx == nil || (!tracer.IsEnabled() && ctx.Done() == nil) {
+		return c, err
+	}
+	return &tracedCache{CInterface: c, ctx: ctx, key: key}, nil
+}
+
+func (t *tracedCache) Get(key string, serialize bool, compress bool) (*Item, error) {
+	span := t.startSpan("cache.Get", 1)
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	item, err := t.CInterface.Get(key, serialize, compress)
+	span.SetError(err)
+	return item, err
+}
+
+func (t *tracedCache) Set(item Item, serialize bool, compress bool) error {
+	span := t.startSpan("cache.Set", 1)
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return err
+	}
+	err := t.CInterface.Set(item, serialize, compress)
+	span.SetError(err)
+	return err
+}
+
+func (t *tracedCache) SetWithTimeout(item Item, serialize bool, compress bool, ttl int32) error {
+	span := t.startSpan("cache.SetWithTimeout", 1)
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return err
+	}
+	err := t.CInterface.SetWithTimeout(item, serialize, compress, ttl)
+	span.SetError(err)
+	return err
+}
+
+func (t *tracedCache) SetIfNotExists(item Item, serialize bool, compress bool, ttl int32) (bool, error) {
+	span := t.startSpan("cache.SetIfNotExists", 1)
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return false, err
+	}
+	set, err := t.CInterface.SetIfNotExists(item, serialize, compress, ttl)
+	span.SetError(err)
+	return set, err
+}
+
+func (t *tracedCache) Delete(key string) error {
+	span := t.startSpan("cache.Delete", 1)
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return err
+	}
+	err := t.CInterface.Delete(key)
+	span.SetError(err)
+	return err
+}
+
+func (t *tracedCache) DeleteBatch(keys []string) error {
+	span := t.startSpan("cache.DeleteBatch", len(keys))
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return err
+	}
+	err := t.CInterface.DeleteBatch(keys)
+	span.SetError(err)
+	return err
+}
+
+func (t *tracedCache) GetBatch(keys []string, serialize bool, compress bool) (map[string]*Item, error) {
+	span := t.startSpan("cache.GetBatch", len(keys))
+	defer span.End()
+	if err := t.ctx.Err(); err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	items, err := t.CInterface.GetBatch(keys, serialize, compress)
+	span.SetError(err)
+	return items, err
+}
+
+// startSpan starts the span of a call for a number of keys, the keys are not recorded as they
+// can have personal data like the user id
+func (t *tracedCache) startSpan(name string, keys int) *tracer.Span {
+	_, span := tracer.StartSpan(t.ctx, name, tracer.SpanKindClient)
+	span.SetAttribute("cache.instance", t.key)
+	span.SetAttribute("cache.keys", strconv.Itoa(keys))
+	return span
+}
@@ -1,6 +1,7 @@
 package sqldb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
@@ -57,6 +58,33 @@ func (obj *MysqlDriver) Execute(query string, args ...interface{}) (sql.Result,
 	return res, nil
 }
 
+//QueryContext executes the query on mysql DB, it is cancelled with ctx
+func (obj *MysqlDriver) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, *SDBError) {
+	rows, err := obj.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, getErrObj(ErrQueryFailure, err.Error())
+	}
+	return rows, nil
+}
+
+//ExecuteContext executes the query, it is cancelled with ctx
+func (obj *MysqlDriver) ExecuteContext(ctx context.Context, query string, args ...interface{}) (sql.Result, *SDBError) {
+	res, err := obj.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, getErrObj(ErrExecuteFailure, err.Error())
+	}
+	return res, nil
+}
+
+//GetTxnObjContext begins a transaction whose statements are cancelled with ctx
+func (obj *MysqlDriver) GetTxnObjContext(ctx context.Context) (*sql.Tx, *SDBError) {
+	txn, err := obj.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, getErrObj(ErrGetTxnFailure, err.Error())
+	}
+	return txn, nil
+}
+
 func (obj *MysqlDriver) GetTxnObj() (*sql.Tx, *SDBError) {
 	txn, err := obj.db.Begin()
 	if err != nil {
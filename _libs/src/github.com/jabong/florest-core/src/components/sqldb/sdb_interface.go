@@ -1,6 +1,7 @@
 package sqldb
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -19,3 +20,13 @@ type SDBInterface interface {
 	// GetTxnObj get transaction object
 	GetTxnObj() (*sql.Tx, *SDBError)
 }
+
+// SDBContextInterface is implemented by the sql db implementations whose calls can be cancelled with a context
+type SDBContextInterface interface {
+	// QueryContext is Query cancelled with ctx
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, *SDBError)
+	// ExecuteContext is Execute cancelled with ctx
+	ExecuteContext(context.Context, string, ...interface{}) (sql.Result, *SDBError)
+	// GetTxnObjContext gets a transaction object, the transaction is rolled back if ctx is done before it is committed
+	GetTxnObjContext(context.Context) (*sql.Tx, *SDBError)
+}
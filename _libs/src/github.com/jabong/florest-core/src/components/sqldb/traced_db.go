@@ -0,0 +1,97 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/tracer"
+)
+
+// tracedDB traces the calls to a db as children of the span in its context, and cancels them with
+// the context if the db supports it
+type tracedDB struct {
+	SDBInterface
+	ctx context.Context
+	key string
+}
+
+// GetWithContext returns the sql db interface for given key, its calls are traced as children of
+// the span in ctx and cancelled with ctx. The statements run in a transaction are part of the span of
+// the caller, the transaction is rolled back if ctx is done before it is committed
+func GetWithContext(ctx context.Context, key string) (SDBInterface, *SDBError) {
+	db, err := Get(key)
+	if err != nil || ctx == nil || (!tracer.IsEnabled() && ctx.Done() == nil) {
+		return db, err
+	}
+	return &tracedDB{SDBInterface: db, ctx: ctx, key: key}, nil
+}
+
+func (t *tracedDB) Query(query string, args ...interface{}) (*sql.Rows, *SDBError) {
+	span := t.startSpan("sqldb.Query", query)
+	defer span.End()
+	var rows *sql.Rows
+	var err *SDBError
+	if cdb, ok := t.SDBInterface.(SDBContextInterface); ok {
+		rows, err = cdb.QueryContext(t.ctx, query, args...)
+	} else {
+		rows, err = t.SDBInterface.Query(query, args...)
+	}
+	if err != nil {
+		span.SetError(err)
+	}
+	return rows, err
+}
+
+func (t *tracedDB) Execute(query string, args ...interface{}) (sql.Result, *SDBError) {
+	span := t.startSpan("sqldb.Execute", query)
+	defer span.End()
+	var res sql.Result
+	var err *SDBError
+	if cdb, ok := t.SDBInterface.(SDBContextInterface); ok {
+		res, err = cdb.ExecuteContext(t.ctx, query, args...)
+	} else {
+		res, err = t.SDBInterface.Execute(query, args...)
+	}
+	if err != nil {
+		span.SetError(err)
+	}
+	return res, err
+}
+
+func (t *tracedDB) Ping() *SDBError {
+	span := t.startSpan("sqldb.Ping", "")
+	defer span.End()
+	err := t.SDBInterface.Ping()
+	if err != nil {
+		span.SetError(err)
+	}
+	return err
+}
+
+func (t *tracedDB) GetTxnObj() (*sql.Tx, *SDBError) {
+	span := t.startSpan("sqldb.GetTxnObj", "")
+	defer span.End()
+	var txn *sql.Tx
+	var err *SDBError
+	if cdb, ok := t.SDBInterface.(SDBContextInterface); ok {
+		txn, err = cdb.GetTxnObjContext(t.ctx)
+	} else {
+		txn, err = t.SDBInterface.GetTxnObj()
+	}
+	if err != nil {
+		span.SetError(err)
+	}
+	return txn, err
+}
+
+// startSpan starts the span of a call. Only the operation of the statement is recorded, its
+// values can be personal data
+func (t *tracedDB) startSpan(name string, query string) *tracer.Span {
+	_, span := tracer.StartSpan(t.ctx, name, tracer.SpanKindClient)
+	span.SetAttribute("db.instance", t.key)
+	if fields := strings.Fields(query); len(fields) > 0 {
+		span.SetAttribute("db.operation", strings.ToUpper(fields[0]))
+	}
+	return span
+}
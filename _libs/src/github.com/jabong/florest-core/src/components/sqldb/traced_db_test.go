@@ -0,0 +1,71 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// contextDB records the context its calls are made with
+type contextDB struct {
+	ctx context.Context
+}
+
+func (c *contextDB) Init(conf *SDBConfig) *SDBError { return nil }
+
+func (c *contextDB) Query(string, ...interface{}) (*sql.Rows, *SDBError) { return nil, nil }
+
+func (c *contextDB) Execute(string, ...interface{}) (sql.Result, *SDBError) { return nil, nil }
+
+func (c *contextDB) Ping() *SDBError { return nil }
+
+func (c *contextDB) Close() *SDBError { return nil }
+
+func (c *contextDB) GetTxnObj() (*sql.Tx, *SDBError) { return nil, nil }
+
+func (c *contextDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, *SDBError) {
+	c.ctx = ctx
+	return nil, nil
+}
+
+func (c *contextDB) ExecuteContext(ctx context.Context, query string, args ...interface{}) (sql.Result, *SDBError) {
+	c.ctx = ctx
+	return nil, nil
+}
+
+func (c *contextDB) GetTxnObjContext(ctx context.Context) (*sql.Tx, *SDBError) {
+	c.ctx = ctx
+	return nil, nil
+}
+
+func TestGetWithContext(t *testing.T) {
+	db := new(contextDB)
+	if err := Set("contextdb", nil, db); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without tracing the db is not wrapped for a context that is never done
+	if got, _ := GetWithContext(context.Background(), "contextdb"); got != db {
+		t.Fatal("Expected the db itself for a context that is never done")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got, err := GetWithContext(ctx, "contextdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Query("SELECT 1")
+	if db.ctx != ctx {
+		t.Error("Expected the query to be made with the context")
+	}
+	db.ctx = nil
+	got.Execute("UPDATE t SET c = 1")
+	if db.ctx != ctx {
+		t.Error("Expected the statement to be made with the context")
+	}
+	db.ctx = nil
+	got.GetTxnObj()
+	if db.ctx != ctx {
+		t.Error("Expected the transaction to be made with the context")
+	}
+}
@@ -1,8 +1,13 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
+	"github.com/jabong/florest-core/src/common/constants"
 	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/tracer"
+	"runtime/debug"
+	"time"
 )
 
 /*
@@ -11,14 +16,45 @@ Instance of the workflow
 type Orchestrator struct {
 	//Workflow definition
 	workflow *WorkFlowDefinition
+	//Hooks run when the workflow ends with an error
+	failureHooks []FailureHook
 }
 
 /*
-TODO: This should read the workflow configuration file
-and create the appropriate orchestrator
+Hook run when a workflow ends with an error, e.g. to undo what its first nodes did. It gets the data
+the workflow ended with and the error of the failed node
+*/
+type FailureHook func(wfData *WorkFlowData, err error)
+
+/*
+Add a hook run when the workflow ends with an error, the hooks run in the order added
+*/
+func (o *Orchestrator) AddFailureHook(hook FailureHook) {
+	if hook != nil {
+		o.failureHooks = append(o.failureHooks, hook)
+	}
+}
+
+/*
+Create the orchestrator from a workflow configuration file
 */
 func (o *Orchestrator) CreateFromConfig(fileName string) error {
-	return nil
+	workflowdefinition := new(WorkFlowDefinition)
+	if err := workflowdefinition.CreateFromConfig(fileName); err != nil {
+		return err
+	}
+	return o.Create(workflowdefinition)
+}
+
+/*
+Create the orchestrator from a workflow configuration
+*/
+func (o *Orchestrator) CreateFromWorkFlowConfig(conf *WorkFlowConfig) error {
+	workflowdefinition := new(WorkFlowDefinition)
+	if err := workflowdefinition.CreateFromWorkFlowConfig(conf); err != nil {
+		return err
+	}
+	return o.Create(workflowdefinition)
 }
 
 /*
@@ -42,11 +78,23 @@ func execExecuteNode(execNodeID string,
 
 	nextwfData = wfData
 
-	outputData, err := execNode.Execute(*wfData)
+	start := time.Now()
+	//The node span is a child of the request span, as are the spans of the calls the node makes
+	_, span := tracer.StartSpan(wfData.Context(), execNode.Name(), tracer.SpanKindInternal)
+	span.SetAttribute("workflow.node_id", execNodeID)
+	defer span.End()
+	outputData, err := execWithTimeout(execNode, wfData, wfDefinition.timeouts[execNodeID])
 	if err != nil {
+		recordNodeTiming(wfData.ExecContext, execNodeID, execNode.Name(), start, getNodeOutcome(err))
+		span.SetAttribute("workflow.outcome", getNodeOutcome(err))
+		span.SetError(err)
 		nextwfData.setWorkflowState(execNode.Name(), err)
 		return "", nextwfData
 	}
+	recordNodeTiming(wfData.ExecContext, execNodeID, execNode.Name(), start, NodeSucceeded)
+	span.SetAttribute("workflow.outcome", NodeSucceeded)
+	//The node saw its own deadline, the next nodes get the context of the workflow
+	outputData.ctx = wfData.ctx
 	nextwfData = &outputData
 	nextNodeIDs, found := wfDefinition.edges[execNodeID]
 
@@ -58,6 +106,86 @@ func execExecuteNode(execNodeID string,
 	return nextNodeID, nextwfData
 }
 
+//execWithTimeout runs an execute node. A node with a timeout runs in its own goroutine with the deadline
+//in its context and is abandoned when the deadline passes or the request is cancelled. A node without a
+//timeout runs with the context of the request, the calls it makes with that context are cancelled with it
+func execWithTimeout(execNode WorkFlowExecuteNodeInterface,
+	wfData *WorkFlowData,
+	timeout time.Duration) (WorkFlowData, error) {
+
+	ctx := wfData.Context()
+	if timeout <= 0 {
+		data, err := execNode.Execute(*wfData)
+		//The node stopped because the request was cancelled
+		if ctx.Err() != nil {
+			return data, getContextError(ctx, execNode.Name())
+		}
+		return data, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	nodeData := *wfData
+	nodeData.ctx = ctx
+	type nodeResult struct {
+		data     WorkFlowData
+		err      error
+		panicked interface{}
+	}
+	done := make(chan nodeResult, 1)
+	go func() {
+		var res nodeResult
+		defer func() {
+			//A panic is raised again on the request goroutine, as if the node ran on it. The stack
+			//of the node is lost then, so it is logged here
+			if r := recover(); r != nil {
+				logger.Error(fmt.Sprintf("Node %s panicked - %v\n%s", execNode.Name(), r, debug.Stack()))
+				res.panicked = r
+			}
+			done <- res
+		}()
+		res.data, res.err = execNode.Execute(nodeData)
+	}()
+
+	select {
+	case res := <-done:
+		if res.panicked != nil {
+			panic(res.panicked)
+		}
+		//The node failed because it ran out of its deadline
+		if res.err != nil && ctx.Err() != nil {
+			return res.data, getContextError(wfData.Context(), execNode.Name())
+		}
+		return res.data, res.err
+	case <-ctx.Done():
+		logger.Error(fmt.Sprintf("Node %s abandoned - %v", execNode.Name(), ctx.Err()))
+		return *wfData, getContextError(wfData.Context(), execNode.Name())
+	}
+}
+
+//getContextError is the error of a node stopped by the deadline or the cancellation of a context
+func getContextError(parent context.Context, nodeName string) error {
+	if parent.Err() == context.Canceled {
+		return &constants.AppError{Code: constants.RequestCancelledErrorCode,
+			Message: fmt.Sprintf("Request cancelled before %s finished", nodeName)}
+	}
+	return &constants.AppError{Code: constants.NodeTimeoutErrorCode,
+		Message: fmt.Sprintf("%s did not finish in time", nodeName)}
+}
+
+//getNodeOutcome tells a timeout or a cancellation apart from a node error
+func getNodeOutcome(err error) string {
+	if appErr, ok := err.(*constants.AppError); ok {
+		switch appErr.Code {
+		case constants.NodeTimeoutErrorCode:
+			return NodeTimedOut
+		case constants.RequestCancelledErrorCode:
+			return NodeCancelled
+		}
+	}
+	return NodeFailed
+}
+
 //Helper function to execute the Decision Node
 func execDecisionNode(decisionNodeID string,
 	decisionNode WorkFlowDecisionNodeInterface,
@@ -66,11 +194,14 @@ func execDecisionNode(decisionNodeID string,
 
 	nextwfData = wfData
 
+	start := time.Now()
 	yes, err := decisionNode.GetDecision(*wfData)
 	if err != nil {
+		recordNodeTiming(wfData.ExecContext, decisionNodeID, decisionNode.Name(), start, NodeFailed)
 		nextwfData.setWorkflowState(decisionNode.Name(), err)
 		return "", nextwfData
 	}
+	recordNodeTiming(wfData.ExecContext, decisionNodeID, decisionNode.Name(), start, NodeSucceeded)
 
 	nextNodeIDs, found := wfDefinition.edges[decisionNodeID]
 
@@ -148,11 +279,20 @@ func executeJoinNode(joinNodeID string,
 	wfDefinition *WorkFlowDefinition) (nextNodeID string, nextwfData *WorkFlowData) {
 
 	nextwfData = forkWfData
+	if err := forkWfData.Context().Err(); err != nil {
+		recordNodeTiming(forkWfData.ExecContext, joinNodeID, joinNode.Name(), time.Now(), NodeCancelled)
+		nextwfData.setWorkflowState(joinNode.Name(), getContextError(forkWfData.Context(), joinNode.Name()))
+		return "", nextwfData
+	}
+	start := time.Now()
 	outputData, err := joinNode.Join(joinWfData)
 	if err != nil {
+		recordNodeTiming(forkWfData.ExecContext, joinNodeID, joinNode.Name(), start, NodeFailed)
 		nextwfData.setWorkflowState(joinNode.Name(), err)
 		return "", nextwfData
 	}
+	recordNodeTiming(forkWfData.ExecContext, joinNodeID, joinNode.Name(), start, NodeSucceeded)
+	outputData.ctx = forkWfData.ctx
 	nextwfData = &outputData
 
 	nextNodeIDs, found := wfDefinition.edges[joinNodeID]
@@ -186,6 +326,13 @@ func run(currNodeID string,
 	}
 	logger.Info("Current Node : " + node.Name())
 
+	//The client went away or the request ran out of time, the remaining nodes are not run
+	if ctx := wfData.Context(); ctx.Err() != nil {
+		recordNodeTiming(wfData.ExecContext, currNodeID, node.Name(), time.Now(), getNodeOutcome(getContextError(ctx, node.Name())))
+		wfData.setWorkflowState(node.Name(), getContextError(ctx, node.Name()))
+		return wfData
+	}
+
 	var nextNodeID string
 	nextwfData := wfData
 
@@ -226,13 +373,46 @@ func (o *Orchestrator) Start(wfData *WorkFlowData) *WorkFlowData {
 		logger.Error("Error Empty workflow definition passed for execution")
 		return new(WorkFlowData)
 	}
-	return run(o.workflow.startNodeID, o.workflow, wfData, "")
+	initNodeTimings(wfData.ExecContext)
+	output := run(o.workflow.startNodeID, o.workflow, wfData, "")
+	if len(o.failureHooks) != 0 {
+		if err := getWorkflowError(output); err != nil {
+			for _, hook := range o.failureHooks {
+				hook(output, err)
+			}
+		}
+	}
+	return output
+}
+
+//getWorkflowError gets the error a node of the workflow failed with, nil if none failed
+func getWorkflowError(wfData *WorkFlowData) error {
+	for _, state := range wfData.GetWorkflowState() {
+		if err, ok := state.(error); ok {
+			return err
+		}
+	}
+	return nil
 }
 
 func (o *Orchestrator) String() string {
 	return o.workflow.String()
 }
 
+/*
+Render the workflow graph in the Graphviz DOT language
+*/
+func (o *Orchestrator) DOT() string {
+	return o.workflow.DOT()
+}
+
+/*
+Render the workflow graph as a Mermaid flowchart
+*/
+func (o *Orchestrator) Mermaid() string {
+	return o.workflow.Mermaid()
+}
+
 /*
 Orchestrator implements the version manager GetInstance
 */
@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+//Latency of an independent read, like a database query or a decryption call
+const benchmarkReadLatency = 2 * time.Millisecond
+
+type benchmarkReadNode struct {
+	id string
+}
+
+func (n benchmarkReadNode) Name() string {
+	return "Benchmark Read Node"
+}
+
+func (n *benchmarkReadNode) SetID(id string) {
+	n.id = id
+}
+
+func (n benchmarkReadNode) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n benchmarkReadNode) Execute(data WorkFlowData) (WorkFlowData, error) {
+	time.Sleep(benchmarkReadLatency)
+	return data, nil
+}
+
+type benchmarkForkNode struct {
+	testForkNode
+}
+
+type benchmarkJoinNode struct {
+	testJoinNode
+}
+
+func (n benchmarkJoinNode) Join(data []*WorkFlowData) (WorkFlowData, error) {
+	return *data[0], nil
+}
+
+func benchmarkWorkflow(b *testing.B, conf *WorkFlowConfig) {
+	RegisterNode("benchmarkReadNode", func() WorkFlowNodeInterface { return new(benchmarkReadNode) })
+	RegisterNode("benchmarkForkNode", func() WorkFlowNodeInterface { return new(benchmarkForkNode) })
+	RegisterNode("benchmarkJoinNode", func() WorkFlowNodeInterface { return new(benchmarkJoinNode) })
+	testOrchestrator := new(Orchestrator)
+	if err := testOrchestrator.CreateFromWorkFlowConfig(conf); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		testOrchestrator.Start(createTestWorkflowData())
+	}
+}
+
+/*
+Benchmark three independent reads run one after another
+*/
+func BenchmarkSequentialReads(b *testing.B) {
+	benchmarkWorkflow(b, &WorkFlowConfig{Start: "1", Nodes: []*WorkFlowNodeConfig{
+		{ID: "1", Type: ExecuteNodeType, Node: "benchmarkReadNode", Next: []string{"2"}},
+		{ID: "2", Type: ExecuteNodeType, Node: "benchmarkReadNode", Next: []string{"3"}},
+		{ID: "3", Type: ExecuteNodeType, Node: "benchmarkReadNode"},
+	}})
+}
+
+/*
+Benchmark three independent reads run on forked paths
+*/
+func BenchmarkForkedReads(b *testing.B) {
+	benchmarkWorkflow(b, &WorkFlowConfig{Start: "F", Nodes: []*WorkFlowNodeConfig{
+		{ID: "F", Type: ForkNodeType, Node: "benchmarkForkNode", Next: []string{"1", "2", "3"}},
+		{ID: "1", Type: ExecuteNodeType, Node: "benchmarkReadNode", Next: []string{"J"}},
+		{ID: "2", Type: ExecuteNodeType, Node: "benchmarkReadNode", Next: []string{"J"}},
+		{ID: "3", Type: ExecuteNodeType, Node: "benchmarkReadNode", Next: []string{"J"}},
+		{ID: "J", Type: JoinNodeType, Node: "benchmarkJoinNode"},
+	}})
+}
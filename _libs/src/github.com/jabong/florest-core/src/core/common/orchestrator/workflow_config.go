@@ -0,0 +1,252 @@
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+/*
+Node types of a workflow configuration
+*/
+const (
+	ExecuteNodeType  = "execute"
+	DecisionNodeType = "decision"
+	ForkNodeType     = "fork"
+	JoinNodeType     = "join"
+)
+
+/*
+Creates a new instance of a workflow node
+*/
+type NodeConstructor func() WorkFlowNodeInterface
+
+//Nodes the workflow configurations can refer to, by name
+var nodeRegistry = make(map[string]NodeConstructor)
+
+/*
+Register a node under a name so that workflow configurations can refer to it.
+Every workflow using the node gets its own instance
+*/
+func RegisterNode(name string, constructor NodeConstructor) {
+	nodeRegistry[name] = constructor
+}
+
+/*
+Declarative definition of a workflow
+*/
+type WorkFlowConfig struct {
+	//Id of the node the workflow starts with
+	Start string `json:"start"`
+
+	Nodes []*WorkFlowNodeConfig `json:"nodes"`
+}
+
+/*
+A node of a workflow configuration. Next is the node following an execute or join
+node, or the nodes forked by a fork node. Yes and No are the nodes following a decision node.
+Timeout, like "2s", is the time an execute node is allowed to run for
+*/
+type WorkFlowNodeConfig struct {
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Node    string   `json:"node"`
+	Next    []string `json:"next,omitempty"`
+	Yes     string   `json:"yes,omitempty"`
+	No      string   `json:"no,omitempty"`
+	Timeout string   `json:"timeout,omitempty"`
+}
+
+/*
+Read a file of workflow configurations by workflow name
+*/
+func LoadWorkFlowConfigs(fileName string) (map[string]*WorkFlowConfig, error) {
+	confs := make(map[string]*WorkFlowConfig)
+	if err := readWorkFlowConfig(fileName, &confs); err != nil {
+		return nil, err
+	}
+	return confs, nil
+}
+
+func readWorkFlowConfig(fileName string, conf interface{}) error {
+	file, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("Error loading workflow config file %s - %v", fileName, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(conf); err != nil {
+		return fmt.Errorf("Incorrect json in workflow config file %s - %v", fileName, err)
+	}
+	return nil
+}
+
+/*
+Create the workflow definition from a workflow configuration. The graph is validated:
+the nodes have to be registered and of their declared type, every node has to be reachable
+from the start node, there can be no cycles and every fork needs its join
+*/
+func (d *WorkFlowDefinition) CreateFromWorkFlowConfig(conf *WorkFlowConfig) error {
+	d.Create()
+	if conf == nil || len(conf.Nodes) == 0 {
+		return fmt.Errorf("Workflow config has no nodes")
+	}
+	for _, nodeConf := range conf.Nodes {
+		if err := d.addConfigNode(nodeConf); err != nil {
+			return err
+		}
+	}
+	for _, nodeConf := range conf.Nodes {
+		if err := d.addConfigEdges(nodeConf); err != nil {
+			return err
+		}
+	}
+
+	startNode, found := d.nodes[conf.Start]
+	if !found {
+		return fmt.Errorf("Start node %q is not a node of the workflow", conf.Start)
+	}
+	if err := d.SetStartNode(startNode); err != nil {
+		return err
+	}
+	if err := d.validateGraph(); err != nil {
+		return err
+	}
+	if err := d.createJoinForkMapping(); err != nil {
+		return err
+	}
+	for id, node := range d.nodes {
+		if _, ok := node.(WorkFlowForkNodeInterface); !ok {
+			continue
+		}
+		if _, found := d.joinFork[id]; !found {
+			return fmt.Errorf("Fork node %q has no join node", id)
+		}
+	}
+	return nil
+}
+
+func (d *WorkFlowDefinition) addConfigNode(nodeConf *WorkFlowNodeConfig) error {
+	if nodeConf == nil || nodeConf.ID == "" {
+		return fmt.Errorf("Workflow config has a node without id")
+	}
+	if _, found := d.nodes[nodeConf.ID]; found {
+		return fmt.Errorf("Node id %q is used more than once", nodeConf.ID)
+	}
+	constructor, found := nodeRegistry[nodeConf.Node]
+	if !found {
+		return fmt.Errorf("Node %q of node id %q is not registered", nodeConf.Node, nodeConf.ID)
+	}
+	node := constructor()
+	if node == nil {
+		return fmt.Errorf("Node %q of node id %q could not be created", nodeConf.Node, nodeConf.ID)
+	}
+	var ok bool
+	switch nodeConf.Type {
+	case ExecuteNodeType:
+		_, ok = node.(WorkFlowExecuteNodeInterface)
+	case DecisionNodeType:
+		_, ok = node.(WorkFlowDecisionNodeInterface)
+	case ForkNodeType:
+		_, ok = node.(WorkFlowForkNodeInterface)
+	case JoinNodeType:
+		_, ok = node.(WorkFlowJoinNodeInterface)
+	default:
+		return fmt.Errorf("Node id %q has unknown type %q", nodeConf.ID, nodeConf.Type)
+	}
+	if !ok {
+		return fmt.Errorf("Node %q of node id %q is not a %s node", nodeConf.Node, nodeConf.ID, nodeConf.Type)
+	}
+	node.SetID(nodeConf.ID)
+	d.nodes[nodeConf.ID] = node
+	if nodeConf.Timeout == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(nodeConf.Timeout)
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("Node id %q has an invalid timeout %q", nodeConf.ID, nodeConf.Timeout)
+	}
+	execNode, ok := node.(WorkFlowExecuteNodeInterface)
+	if !ok || nodeConf.Type != ExecuteNodeType {
+		return fmt.Errorf("Node id %q has a timeout, only execute nodes can have one", nodeConf.ID)
+	}
+	return d.SetNodeTimeout(execNode, timeout)
+}
+
+func (d *WorkFlowDefinition) addConfigEdges(nodeConf *WorkFlowNodeConfig) error {
+	var next []string
+	switch nodeConf.Type {
+	case DecisionNodeType:
+		if nodeConf.Yes == "" || nodeConf.No == "" || len(nodeConf.Next) != 0 {
+			return fmt.Errorf("Decision node %q needs a yes and a no node and no next", nodeConf.ID)
+		}
+		next = []string{nodeConf.Yes, nodeConf.No}
+	case ForkNodeType:
+		if len(nodeConf.Next) == 0 || nodeConf.Yes != "" || nodeConf.No != "" {
+			return fmt.Errorf("Fork node %q needs at least one next node and no yes or no", nodeConf.ID)
+		}
+		next = nodeConf.Next
+	default:
+		if len(nodeConf.Next) > 1 || nodeConf.Yes != "" || nodeConf.No != "" {
+			return fmt.Errorf("%s node %q can have at most one next node and no yes or no", nodeConf.Type, nodeConf.ID)
+		}
+		next = nodeConf.Next
+	}
+	for _, nextID := range next {
+		if _, found := d.nodes[nextID]; !found {
+			return fmt.Errorf("Node id %q connects to %q, which is not a node of the workflow", nodeConf.ID, nextID)
+		}
+	}
+	if len(next) != 0 {
+		d.edges[nodeConf.ID] = next
+	}
+	return nil
+}
+
+//validateGraph checks that the workflow has no cycles and every node is reachable from the start node
+func (d *WorkFlowDefinition) validateGraph() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(d.nodes))
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("Workflow has a cycle through node id %q", id)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, nextID := range d.edges[id] {
+			if err := visit(nextID); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+	if err := visit(d.startNodeID); err != nil {
+		return err
+	}
+	for id := range d.nodes {
+		if state[id] != visited {
+			return fmt.Errorf("Node id %q can not be reached from the start node", id)
+		}
+	}
+	return nil
+}
+
+/*
+Create the workflow definition from a workflow configuration file
+*/
+func (d *WorkFlowDefinition) CreateFromConfig(filename string) error {
+	conf := new(WorkFlowConfig)
+	if err := readWorkFlowConfig(filename, conf); err != nil {
+		return err
+	}
+	return d.CreateFromWorkFlowConfig(conf)
+}
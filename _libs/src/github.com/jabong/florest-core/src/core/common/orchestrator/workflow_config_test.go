@@ -0,0 +1,116 @@
+package orchestrator
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func registerTestConfigNodes() {
+	RegisterNode("testExecNode", func() WorkFlowNodeInterface { return new(testExecNode) })
+	RegisterNode("testDecisionNode", func() WorkFlowNodeInterface { return new(testDecisionNode) })
+	RegisterNode("testYesNode", func() WorkFlowNodeInterface { return new(testYesNode) })
+	RegisterNode("testNoNode", func() WorkFlowNodeInterface { return new(testNoNode) })
+	RegisterNode("testForkNode", func() WorkFlowNodeInterface { return new(testForkNode) })
+	RegisterNode("testJoinNode", func() WorkFlowNodeInterface { return new(testJoinNode) })
+}
+
+const testWorkFlowConfig = `{
+	"start": "F1",
+	"nodes": [
+		{"id": "F1", "type": "fork", "node": "testForkNode", "next": ["E1", "E2"]},
+		{"id": "E1", "type": "execute", "node": "testExecNode", "next": ["J1"]},
+		{"id": "E2", "type": "execute", "node": "testExecNode", "next": ["E3"]},
+		{"id": "E3", "type": "execute", "node": "testExecNode", "next": ["J1"]},
+		{"id": "J1", "type": "join", "node": "testJoinNode", "next": ["D1"]},
+		{"id": "D1", "type": "decision", "node": "testDecisionNode", "yes": "Y1", "no": "N1"},
+		{"id": "Y1", "type": "execute", "node": "testYesNode"},
+		{"id": "N1", "type": "execute", "node": "testNoNode"}
+	]
+}`
+
+/*
+Test for creating and running an orchestrator from a workflow configuration file
+*/
+func TestOrchestratorCreateFromConfig(t *testing.T) {
+	registerTestConfigNodes()
+	file, err := ioutil.TempFile("", "workflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(testWorkFlowConfig)
+	file.Close()
+
+	testOrchestrator := new(Orchestrator)
+	if err = testOrchestrator.CreateFromConfig(file.Name()); err != nil {
+		t.Fatalf("Failed to create orchestrator from config - %v", err)
+	}
+	if testOrchestrator.workflow.joinFork["F1"] != "J1" {
+		t.Error("Fork node not mapped to its join node")
+	}
+
+	testWorkFlowData := createTestWorkflowData()
+	testWorkFlowData.IOData.Set(dECISION, false)
+	outputData := testOrchestrator.Start(testWorkFlowData)
+
+	wfSate := outputData.GetWorkflowState()
+	if _, found := wfSate[nONODENAME]; !found {
+		t.Error("Failed to execute orchestrator created from config")
+	}
+}
+
+/*
+Test that invalid workflow configurations are rejected
+*/
+func TestWorkflowDefinitionCreateFromInvalidConfig(t *testing.T) {
+	registerTestConfigNodes()
+	tests := map[string]*WorkFlowConfig{
+		"not registered": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "unknownNode"},
+		}},
+		"is not a decision node": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: DecisionNodeType, Node: "testExecNode", Yes: "1", No: "1"},
+		}},
+		"used more than once": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode"},
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode"},
+		}},
+		"not a node of the workflow": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode", Next: []string{"2"}},
+		}},
+		"can not be reached": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode"},
+			{ID: "2", Type: ExecuteNodeType, Node: "testExecNode"},
+		}},
+		"cycle": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode", Next: []string{"2"}},
+			{ID: "2", Type: ExecuteNodeType, Node: "testExecNode", Next: []string{"1"}},
+		}},
+		"needs a yes and a no": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: DecisionNodeType, Node: "testDecisionNode", Yes: "2"},
+			{ID: "2", Type: ExecuteNodeType, Node: "testYesNode"},
+		}},
+		"has no join node": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ForkNodeType, Node: "testForkNode", Next: []string{"2"}},
+			{ID: "2", Type: ExecuteNodeType, Node: "testExecNode"},
+		}},
+		"invalid timeout": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode", Timeout: "soon"},
+		}},
+		"only execute nodes": {Start: "1", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: DecisionNodeType, Node: "testDecisionNode", Yes: "2", No: "2", Timeout: "1s"},
+			{ID: "2", Type: ExecuteNodeType, Node: "testExecNode"},
+		}},
+		"Start node": {Start: "2", Nodes: []*WorkFlowNodeConfig{
+			{ID: "1", Type: ExecuteNodeType, Node: "testExecNode"},
+		}},
+	}
+	for want, conf := range tests {
+		err := new(WorkFlowDefinition).CreateFromWorkFlowConfig(conf)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package orchestrator
 
+import (
+	"context"
+)
+
 /*
 Data structure to hold the workflow data passed from one node to the other
 */
@@ -7,6 +11,8 @@ type WorkFlowData struct {
 	IOData      WorkFlowIOInterface
 	ExecContext WorkFlowExecutionContextInterface
 	state       workFlowState
+	//Context of the request, its cancellation stops the workflow
+	ctx context.Context
 }
 
 /*
@@ -43,5 +49,24 @@ func (d *WorkFlowData) Clone() WorkFlowData {
 	ioCloneData, _ := ioClone.(WorkFlowIOInterface)
 	return WorkFlowData{IOData: ioCloneData,
 		ExecContext: d.ExecContext,
-		state:       d.state}
+		state:       d.state,
+		ctx:         d.ctx}
+}
+
+/*
+Get the context of the workflow data. A node with a timeout gets a context with its deadline,
+which it can pass on to the calls it makes
+*/
+func (d *WorkFlowData) Context() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
+/*
+Set the context of the workflow data, usually the context of the http request
+*/
+func (d *WorkFlowData) SetContext(ctx context.Context) {
+	d.ctx = ctx
 }
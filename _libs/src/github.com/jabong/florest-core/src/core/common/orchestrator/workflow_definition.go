@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jabong/florest-core/src/common/collections/stack"
+	"time"
 )
 
 /*
@@ -22,22 +23,40 @@ type WorkFlowDefinition struct {
 	joinFork map[string]string
 
 	startNodeID string
-}
 
-/*
-TODO: Create the workflow definition from configuration file
-*/
-func (d *WorkFlowDefinition) CreateFromConfig(filename string) error {
-	return nil
+	//Time an execute node is allowed to run for
+	timeouts map[string]time.Duration
 }
 
 /*
-TODO: Create the workflow definition from configuration file
+Create an empty workflow definition, the nodes and connections are added to it
 */
 func (d *WorkFlowDefinition) Create() {
 	d.nodes = make(map[string]WorkFlowNodeInterface)
 	d.edges = make(map[string][]string)
 	d.joinFork = make(map[string]string)
+	d.timeouts = make(map[string]time.Duration)
+}
+
+/*
+Set the time an execute node is allowed to run for. The workflow stops with a timeout error
+when the node does not finish in time
+*/
+func (d *WorkFlowDefinition) SetNodeTimeout(execNode WorkFlowExecuteNodeInterface, timeout time.Duration) error {
+	id, iderr := execNode.GetID()
+	if iderr != nil {
+		return iderr
+	}
+
+	if _, found := d.nodes[id]; !found {
+		return fmt.Errorf("Node with provide Id: %s is not added", id)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("Timeout of node id %s must be positive", id)
+	}
+
+	d.timeouts[id] = timeout
+	return nil
 }
 
 /*
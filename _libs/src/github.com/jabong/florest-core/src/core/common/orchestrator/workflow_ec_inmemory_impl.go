@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"errors"
 	"fmt"
+	"sync"
 )
 
 const (
@@ -13,6 +14,8 @@ const (
 )
 
 type WorkFlowECInMemoryImpl struct {
+	//Forked paths and nodes abandoned on timeout share the execution context
+	mutex sync.RWMutex
 	store map[string]interface{}
 }
 
@@ -22,6 +25,8 @@ type WorkflowDebugDataInMemory struct {
 }
 
 func (ec *WorkFlowECInMemoryImpl) Get(key string) (value interface{}, err error) {
+	ec.mutex.RLock()
+	defer ec.mutex.RUnlock()
 	//Check if the key is already present
 	res, found := ec.store[key]
 	if !found {
@@ -32,6 +37,8 @@ func (ec *WorkFlowECInMemoryImpl) Get(key string) (value interface{}, err error)
 }
 
 func (ec *WorkFlowECInMemoryImpl) Set(key string, value interface{}) (err error) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
 	if ec.store == nil {
 		ec.store = make(map[string]interface{})
 	}
@@ -64,6 +71,8 @@ func (ec *WorkFlowECInMemoryImpl) SetDebugFlag(flag bool) (err error) {
 }
 
 func (ec *WorkFlowECInMemoryImpl) SetDebugMsg(msgkey string, msgData string) (err error) {
+	ec.mutex.Lock()
+	defer ec.mutex.Unlock()
 	if ec.store == nil {
 		ec.store = make(map[string]interface{})
 	}
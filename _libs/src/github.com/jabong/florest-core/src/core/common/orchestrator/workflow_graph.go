@@ -0,0 +1,133 @@
+package orchestrator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+Render the workflow graph in the Graphviz DOT language. A node is labelled with its id and name,
+the edges of a decision node with yes and no
+*/
+func (d *WorkFlowDefinition) DOT() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph workflow {\n")
+	buf.WriteString("\tstart [shape=point];\n")
+	for _, id := range d.sortedNodeIds() {
+		shape := map[string]string{
+			ExecuteNodeType:  "box",
+			DecisionNodeType: "diamond",
+			ForkNodeType:     "trapezium",
+			JoinNodeType:     "invtrapezium",
+		}[nodeType(d.nodes[id])]
+		fmt.Fprintf(&buf, "\t%s [label=%s, shape=%s];\n", dotQuote(id), dotQuote(d.nodeLabel(id)), shape)
+	}
+	if d.startNodeID != "" {
+		fmt.Fprintf(&buf, "\tstart -> %s;\n", dotQuote(d.startNodeID))
+	}
+	d.eachEdge(func(from string, to string, label string) {
+		if label == "" {
+			fmt.Fprintf(&buf, "\t%s -> %s;\n", dotQuote(from), dotQuote(to))
+			return
+		}
+		fmt.Fprintf(&buf, "\t%s -> %s [label=%s];\n", dotQuote(from), dotQuote(to), dotQuote(label))
+	})
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+/*
+Render the workflow graph as a Mermaid flowchart. A node is labelled with its id and name,
+the edges of a decision node with yes and no
+*/
+func (d *WorkFlowDefinition) Mermaid() string {
+	var buf bytes.Buffer
+	buf.WriteString("flowchart TD\n")
+	buf.WriteString("\tstart((start))\n")
+	for _, id := range d.sortedNodeIds() {
+		shape := map[string][2]string{
+			ExecuteNodeType:  {"[", "]"},
+			DecisionNodeType: {"{", "}"},
+			ForkNodeType:     {"[/", "\\]"},
+			JoinNodeType:     {"[\\", "/]"},
+		}[nodeType(d.nodes[id])]
+		fmt.Fprintf(&buf, "\t%s%s%s%s\n", mermaidID(id), shape[0], mermaidQuote(d.nodeLabel(id)), shape[1])
+	}
+	if d.startNodeID != "" {
+		fmt.Fprintf(&buf, "\tstart --> %s\n", mermaidID(d.startNodeID))
+	}
+	d.eachEdge(func(from string, to string, label string) {
+		if label == "" {
+			fmt.Fprintf(&buf, "\t%s --> %s\n", mermaidID(from), mermaidID(to))
+			return
+		}
+		fmt.Fprintf(&buf, "\t%s -->|%s| %s\n", mermaidID(from), label, mermaidID(to))
+	})
+	return buf.String()
+}
+
+//nodeType is the type of a node, in the order the orchestrator checks them
+func nodeType(node WorkFlowNodeInterface) string {
+	switch node.(type) {
+	case WorkFlowExecuteNodeInterface:
+		return ExecuteNodeType
+	case WorkFlowDecisionNodeInterface:
+		return DecisionNodeType
+	case WorkFlowForkNodeInterface:
+		return ForkNodeType
+	default:
+		return JoinNodeType
+	}
+}
+
+func (d *WorkFlowDefinition) sortedNodeIds() []string {
+	ids := make([]string, 0, len(d.nodes))
+	for id := range d.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (d *WorkFlowDefinition) nodeLabel(id string) string {
+	return id + ": " + d.nodes[id].Name()
+}
+
+//eachEdge calls fn for every connection in node id order, a decision node has a yes and a no connection
+func (d *WorkFlowDefinition) eachEdge(fn func(from string, to string, label string)) {
+	for _, from := range d.sortedNodeIds() {
+		edges := d.edges[from]
+		if nodeType(d.nodes[from]) == DecisionNodeType && len(edges) == 2 {
+			fn(from, edges[0], "yes")
+			fn(from, edges[1], "no")
+			continue
+		}
+		for _, to := range edges {
+			fn(from, to, "")
+		}
+	}
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+//mermaidID prefixes the node id, Mermaid ids can not hold every character a node id can
+func mermaidID(id string) string {
+	var buf bytes.Buffer
+	buf.WriteString("n_")
+	for _, r := range id {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			buf.WriteRune(r)
+		} else {
+			fmt.Fprintf(&buf, "_%x_", r)
+		}
+	}
+	return buf.String()
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+}
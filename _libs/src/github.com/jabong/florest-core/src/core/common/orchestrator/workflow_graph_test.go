@@ -0,0 +1,65 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func createTestGraphOrchestrator(t *testing.T) *Orchestrator {
+	registerTestConfigNodes()
+	conf := &WorkFlowConfig{Start: "F1", Nodes: []*WorkFlowNodeConfig{
+		{ID: "F1", Type: ForkNodeType, Node: "testForkNode", Next: []string{"E1"}},
+		{ID: "E1", Type: ExecuteNodeType, Node: "testExecNode", Next: []string{"J1"}},
+		{ID: "J1", Type: JoinNodeType, Node: "testJoinNode", Next: []string{"D1"}},
+		{ID: "D1", Type: DecisionNodeType, Node: "testDecisionNode", Yes: "Y1", No: "N1"},
+		{ID: "Y1", Type: ExecuteNodeType, Node: "testYesNode"},
+		{ID: "N1", Type: ExecuteNodeType, Node: "testNoNode"},
+	}}
+	testOrchestrator := new(Orchestrator)
+	if err := testOrchestrator.CreateFromWorkFlowConfig(conf); err != nil {
+		t.Fatalf("Failed to create orchestrator from config - %v", err)
+	}
+	return testOrchestrator
+}
+
+/*
+Test rendering the workflow graph in the DOT language
+*/
+func TestOrchestratorDOT(t *testing.T) {
+	dot := createTestGraphOrchestrator(t).DOT()
+	for _, want := range []string{
+		"digraph workflow {\n",
+		"\t\"D1\" [label=\"D1: " + tESTDECISIONNODENAME + "\", shape=diamond];\n",
+		"\t\"F1\" [label=\"F1: " + tESTWFFORKNODE + "\", shape=trapezium];\n",
+		"\tstart -> \"F1\";\n",
+		"\t\"D1\" -> \"Y1\" [label=\"yes\"];\n",
+		"\t\"D1\" -> \"N1\" [label=\"no\"];\n",
+		"\t\"J1\" -> \"D1\";\n",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected %q in the DOT graph\n%s", want, dot)
+		}
+	}
+}
+
+/*
+Test rendering the workflow graph as a Mermaid flowchart
+*/
+func TestOrchestratorMermaid(t *testing.T) {
+	mermaid := createTestGraphOrchestrator(t).Mermaid()
+	for _, want := range []string{
+		"flowchart TD\n",
+		"\tn_D1{\"D1: " + tESTDECISIONNODENAME + "\"}\n",
+		"\tn_J1[\\\"J1: " + tESTWFJOINNODE + "\"/]\n",
+		"\tstart --> n_F1\n",
+		"\tn_D1 -->|yes| n_Y1\n",
+		"\tn_D1 -->|no| n_N1\n",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Expected %q in the Mermaid graph\n%s", want, mermaid)
+		}
+	}
+	if mermaidID("a-b") != "n_a_2d_b" {
+		t.Errorf("Unexpected Mermaid id %s", mermaidID("a-b"))
+	}
+}
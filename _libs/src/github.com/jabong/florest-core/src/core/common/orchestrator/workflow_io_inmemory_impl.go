@@ -3,13 +3,18 @@ package orchestrator
 import (
 	"errors"
 	"fmt"
+	"sync"
 )
 
 type WorkFlowIOInMemoryImpl struct {
+	//A node abandoned on timeout can still write to the store
+	mutex sync.RWMutex
 	store map[string]interface{}
 }
 
 func (io *WorkFlowIOInMemoryImpl) Get(key string) (value interface{}, err error) {
+	io.mutex.RLock()
+	defer io.mutex.RUnlock()
 	//Check if the key is already present
 	res, found := io.store[key]
 	if !found {
@@ -20,6 +25,8 @@ func (io *WorkFlowIOInMemoryImpl) Get(key string) (value interface{}, err error)
 }
 
 func (io *WorkFlowIOInMemoryImpl) Set(key string, value interface{}) (err error) {
+	io.mutex.Lock()
+	defer io.mutex.Unlock()
 
 	if io.store == nil {
 		io.store = make(map[string]interface{})
@@ -32,6 +39,8 @@ func (io *WorkFlowIOInMemoryImpl) Set(key string, value interface{}) (err error)
 func (io *WorkFlowIOInMemoryImpl) Clone() WorkFlowIOInterface {
 	//you cannot generally call methods on pointers directly on values so a pointer to the interface is created.
 	ioClone := new(WorkFlowIOInMemoryImpl)
+	io.mutex.RLock()
+	defer io.mutex.RUnlock()
 	if io.store == nil {
 		return ioClone
 	}
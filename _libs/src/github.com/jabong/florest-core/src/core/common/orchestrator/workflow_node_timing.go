@@ -0,0 +1,90 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jabong/florest-core/src/common/constants"
+)
+
+/*
+Outcomes of a workflow node run
+*/
+const (
+	NodeSucceeded = "success"
+	NodeFailed    = "error"
+	NodeTimedOut  = "timeout"
+	NodeCancelled = "cancelled"
+)
+
+/*
+Duration and outcome of a workflow node run
+*/
+type NodeTiming struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Outcome  string        `json:"outcome"`
+}
+
+/*
+The node timings of a request, in the order the nodes finished. Forked paths add to them concurrently
+*/
+type NodeTimings struct {
+	mutex   sync.Mutex
+	timings []NodeTiming
+}
+
+func (t *NodeTimings) add(timing NodeTiming) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.timings = append(t.timings, timing)
+}
+
+/*
+Get all the node timings recorded so far
+*/
+func (t *NodeTimings) GetAll() []NodeTiming {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]NodeTiming(nil), t.timings...)
+}
+
+/*
+Get the node timings recorded in the execution context, nil if no workflow ran
+*/
+func GetNodeTimings(ec WorkFlowExecutionContextInterface) []NodeTiming {
+	if ec == nil {
+		return nil
+	}
+	value, _ := ec.Get(constants.NodeTimings)
+	if timings, ok := value.(*NodeTimings); ok {
+		return timings.GetAll()
+	}
+	return nil
+}
+
+//initNodeTimings adds the node timings to the execution context, unless an enclosing workflow already did
+func initNodeTimings(ec WorkFlowExecutionContextInterface) {
+	if ec == nil {
+		return
+	}
+	if value, _ := ec.Get(constants.NodeTimings); value == nil {
+		ec.Set(constants.NodeTimings, new(NodeTimings))
+	}
+}
+
+//recordNodeTiming adds the timing of a node run to the execution context
+func recordNodeTiming(ec WorkFlowExecutionContextInterface, id string, name string, start time.Time, outcome string) {
+	if ec == nil {
+		return
+	}
+	timing := NodeTiming{ID: id, Name: name, Duration: time.Since(start), Outcome: outcome}
+	value, _ := ec.Get(constants.NodeTimings)
+	if timings, ok := value.(*NodeTimings); ok {
+		timings.add(timing)
+	}
+	//Returned in the debug data of the response when the debug header is set
+	ec.SetDebugMsg(fmt.Sprintf("NodeTiming:%s:%s", id, name), fmt.Sprintf("%s in %v", outcome, timing.Duration))
+}
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"errors"
+	"sync"
 )
 
 /*
@@ -11,6 +12,8 @@ This data structure is internal to the orchestrator
 */
 type workFlowState struct {
 	value map[string]interface{}
+	//Shared with the clones of the workflow data, like the value
+	mutex *sync.RWMutex
 }
 
 /*
@@ -18,6 +21,8 @@ Set the value for a  key in the workflow state
 Private to the orchestrator package
 */
 func (state *workFlowState) set(key string, val interface{}) error {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
 	//Check if the key is already present
 	_, ok := state.value[key]
 	if ok {
@@ -31,6 +36,11 @@ func (state *workFlowState) set(key string, val interface{}) error {
 Get the value for a key in the workflow state
 */
 func (state *workFlowState) Get(key string) (val interface{}, err error) {
+	if state.mutex == nil {
+		return "", errors.New("Value not found for " + key)
+	}
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
 	//Check if the key is already present
 	res, ok := state.value[key]
 	if !ok {
@@ -43,7 +53,16 @@ func (state *workFlowState) Get(key string) (val interface{}, err error) {
 Get all the values in the workflow state
 */
 func (state *workFlowState) GetAll() (res map[string]interface{}) {
-	return state.value
+	if state.mutex == nil {
+		return map[string]interface{}{}
+	}
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+	res = make(map[string]interface{}, len(state.value))
+	for key, val := range state.value {
+		res[key] = val
+	}
+	return res
 }
 
 /*
@@ -51,4 +70,5 @@ Initialize the workflow state
 */
 func (state *workFlowState) create() {
 	state.value = make(map[string]interface{})
+	state.mutex = new(sync.RWMutex)
 }
@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jabong/florest-core/src/common/constants"
+)
+
+const tESTSLOWNODENAME = "Test Slow Node"
+
+/*
+Test Slow Node, runs until its context is done
+*/
+type testSlowNode struct {
+	id string
+}
+
+func (n testSlowNode) Name() string {
+	return tESTSLOWNODENAME
+}
+
+func (n *testSlowNode) SetID(id string) {
+	n.id = id
+}
+
+func (n testSlowNode) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n testSlowNode) Execute(data WorkFlowData) (WorkFlowData, error) {
+	select {
+	case <-data.Context().Done():
+	case <-time.After(time.Second):
+	}
+	return data, nil
+}
+
+func createSlowTestOrchestrator(t *testing.T, timeout time.Duration) *Orchestrator {
+	testWorkflow := new(WorkFlowDefinition)
+	testWorkflow.Create()
+	slowNode := new(testSlowNode)
+	slowNode.SetID("1")
+	testWorkflow.AddExecutionNode(slowNode)
+	execNode := new(testExecNode)
+	execNode.SetID("2")
+	testWorkflow.AddExecutionNode(execNode)
+	testWorkflow.AddConnection(slowNode, execNode)
+	testWorkflow.SetStartNode(slowNode)
+	if timeout > 0 {
+		if err := testWorkflow.SetNodeTimeout(slowNode, timeout); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testOrchestrator := new(Orchestrator)
+	testOrchestrator.Create(testWorkflow)
+	return testOrchestrator
+}
+
+func getTestNodeError(t *testing.T, data *WorkFlowData, nodeName string) constants.APPErrorCode {
+	err, _ := data.GetWorkflowState()[nodeName].(*constants.AppError)
+	if err == nil {
+		t.Fatalf("Expected an app error for %s, got %v", nodeName, data.GetWorkflowState())
+	}
+	return err.Code
+}
+
+/*
+Test that a node running past its timeout stops the workflow
+*/
+func TestExecutionNodeTimeout(t *testing.T) {
+	testWorkFlowData := createTestWorkflowData()
+	outputData := createSlowTestOrchestrator(t, 10*time.Millisecond).Start(testWorkFlowData)
+
+	if code := getTestNodeError(t, outputData, tESTSLOWNODENAME); code != constants.NodeTimeoutErrorCode {
+		t.Errorf("Expected a timeout error, got %v", code)
+	}
+	if _, found := outputData.GetWorkflowState()[tESTEXECUTIONNODENAME]; found {
+		t.Error("Node after the timed out node was executed")
+	}
+	timings := GetNodeTimings(testWorkFlowData.ExecContext)
+	if len(timings) != 1 || timings[0].ID != "1" || timings[0].Outcome != NodeTimedOut {
+		t.Errorf("Unexpected node timings %+v", timings)
+	}
+}
+
+/*
+Test that cancelling the request context stops the workflow
+*/
+func TestExecutionNodeCancel(t *testing.T) {
+	testWorkFlowData := createTestWorkflowData()
+	ctx, cancel := context.WithCancel(context.Background())
+	testWorkFlowData.SetContext(ctx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	outputData := createSlowTestOrchestrator(t, 0).Start(testWorkFlowData)
+
+	if code := getTestNodeError(t, outputData, tESTSLOWNODENAME); code != constants.RequestCancelledErrorCode {
+		t.Errorf("Expected a cancelled error, got %v", code)
+	}
+	timings := GetNodeTimings(testWorkFlowData.ExecContext)
+	if len(timings) != 1 || timings[0].Outcome != NodeCancelled {
+		t.Errorf("Unexpected node timings %+v", timings)
+	}
+}
+
+/*
+Test that the nodes finishing in time are timed
+*/
+func TestExecutionNodeTimings(t *testing.T) {
+	testWorkFlowData := createTestWorkflowData()
+	testWorkFlowData.SetContext(context.Background())
+	outputData := createSlowTestOrchestrator(t, time.Minute).Start(testWorkFlowData)
+
+	if _, found := outputData.GetWorkflowState()[tESTEXECUTIONNODENAME]; !found {
+		t.Error("Failed to execute the node after the slow node")
+	}
+	timings := GetNodeTimings(testWorkFlowData.ExecContext)
+	if len(timings) != 2 || timings[0].Name != tESTSLOWNODENAME || timings[1].Name != tESTEXECUTIONNODENAME {
+		t.Fatalf("Unexpected node timings %+v", timings)
+	}
+	for _, timing := range timings {
+		if timing.Outcome != NodeSucceeded {
+			t.Errorf("Unexpected outcome of %s - %s", timing.Name, timing.Outcome)
+		}
+	}
+	if timings[0].Duration < 900*time.Millisecond {
+		t.Errorf("Slow node timed %v", timings[0].Duration)
+	}
+}
+
+/*
+Test that the failure hooks run only when a node fails
+*/
+func TestFailureHook(t *testing.T) {
+	for _, timeout := range []time.Duration{10 * time.Millisecond, time.Minute} {
+		var failures []error
+		testOrchestrator := createSlowTestOrchestrator(t, timeout)
+		testOrchestrator.AddFailureHook(func(wfData *WorkFlowData, err error) {
+			failures = append(failures, err)
+		})
+		testOrchestrator.AddFailureHook(nil)
+		testWorkFlowData := createTestWorkflowData()
+		testWorkFlowData.SetContext(context.Background())
+		testOrchestrator.Start(testWorkFlowData)
+
+		failed := timeout < time.Second
+		if failed && (len(failures) != 1 || getNodeOutcome(failures[0]) != NodeTimedOut) {
+			t.Errorf("Expected the failure hook to run once with the timeout, got %v", failures)
+		}
+		if !failed && len(failures) != 0 {
+			t.Errorf("Expected no failure for a workflow finishing in time, got %v", failures)
+		}
+	}
+}
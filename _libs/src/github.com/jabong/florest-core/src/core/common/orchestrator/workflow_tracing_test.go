@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jabong/florest-core/src/common/tracer"
+)
+
+/*
+Test that an execute node run is traced as a child of the span of the request, with its outcome
+*/
+func TestExecutionNodeSpans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orchestrator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.json")
+	if err := tracer.Initialize(&tracer.TConf{Enabled: true, Exporter: tracer.File, FilePath: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer tracer.Initialize(nil)
+
+	ctx, span := tracer.StartSpan(context.Background(), "request", tracer.SpanKindServer)
+	testWorkFlowData := createTestWorkflowData()
+	testWorkFlowData.SetContext(ctx)
+	createSlowTestOrchestrator(t, 10*time.Millisecond).Start(testWorkFlowData)
+	span.End()
+	tracer.Flush()
+
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := string(body)
+	nodeSpan := `"parentSpanId":"` + span.Context().SpanID + `","name":"` + tESTSLOWNODENAME + `"`
+	if !strings.Contains(spans, nodeSpan) {
+		t.Fatalf("Expected a span of %s child of the request, got %s", tESTSLOWNODENAME, spans)
+	}
+	if !strings.Contains(spans, `{"key":"workflow.outcome","value":{"stringValue":"`+NodeTimedOut+`"}}],"status":{"code":2`) {
+		t.Errorf("Expected the span of %s failed on timeout, got %s", tESTSLOWNODENAME, spans)
+	}
+	if strings.Contains(spans, tESTEXECUTIONNODENAME) {
+		t.Errorf("No span expected for the node after the timed out node, got %s", spans)
+	}
+}
@@ -37,6 +37,15 @@ func ExecuteOrchestrator(input *workflow.WorkFlowData,
 
 	output := orchestrator.Start(input)
 	res, _ := output.IOData.Get(constants.Result)
+	//A workflow ending after a join ends with the io data of a forked path, the response
+	//details set on that path are passed on
+	if output.IOData != nil && output.IOData != input.IOData {
+		for _, key := range []string{constants.ResponseHeaders, constants.ResponseHTTPStatus, constants.ResponseMetaData} {
+			if value, err := output.IOData.Get(key); err == nil {
+				input.IOData.Set(key, value)
+			}
+		}
+	}
 
 	orchestratorStates := output.GetWorkflowState()
 	var orchestratorError error
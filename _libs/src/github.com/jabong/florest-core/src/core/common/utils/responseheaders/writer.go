@@ -49,6 +49,13 @@ func (r *Writer) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error
 	}
 
 	res.Headers[contentType] = "application/json"
+	if h, _ := io.IOData.Get(constants.ResponseHeaders); h != nil {
+		if apiHeaders, ok := h.(map[string]string); ok {
+			for key, val := range apiHeaders {
+				res.Headers[key] = val
+			}
+		}
+	}
 	io.IOData.Set(constants.APIResponse, res)
 	return io, nil
 }
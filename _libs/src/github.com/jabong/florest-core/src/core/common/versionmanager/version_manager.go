@@ -3,6 +3,7 @@ package versionmanager
 import (
 	"errors"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"sort"
 )
 
 /*
@@ -60,3 +61,53 @@ func Get(resource string, version string, action string,
 
 	return versionable, ratelimiter, parameters, nil
 }
+
+/*
+A versionable with the version it is registered for
+*/
+type RegisteredVersionable struct {
+	Version     Version
+	Versionable Versionable
+}
+
+/*
+List every versionable in the version manager, ordered by resource, version, action, bucketId and path
+*/
+func List() ([]RegisteredVersionable, error) {
+	if vmgr == nil {
+		return nil, errors.New("Version manager not initialized")
+	}
+
+	list := []RegisteredVersionable{}
+	for ver, param := range vmgr.mapping {
+		param.walk(nil, func(path string, versionable Versionable) {
+			list = append(list, RegisteredVersionable{
+				Version: Version{
+					Resource: ver.Resource,
+					Version:  ver.Version,
+					Action:   ver.Action,
+					BucketID: ver.BucketID,
+					Path:     path,
+				},
+				Versionable: versionable,
+			})
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].Version, list[j].Version
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.BucketID != b.BucketID {
+			return a.BucketID < b.BucketID
+		}
+		return a.Path < b.Path
+	})
+	return list, nil
+}
@@ -76,11 +76,20 @@ func TestVersionManager(t *testing.T) {
 		Path:     "{bucketId}/buckets/{keyId}/keys",
 	}
 
+	version6 := Version{
+		Resource: resource,
+		Version:  version,
+		Action:   action,
+		BucketID: bucketID,
+		Path:     "{keyId}/values",
+	}
+
 	addTestVersions(version1, vmap, *new(testVersionableImpl))
 	addTestVersions(version2, vmap, *new(testVersionableImpl))
 	addTestVersions(version3, vmap, *new(testVersionableImpl))
 	addTestVersions(version4, vmap, *new(testVersionableImpl))
 	addTestVersions(version5, vmap, *new(testVersionableImpl))
+	addTestVersions(version6, vmap, *new(testVersionableImpl))
 
 	Initialize(vmap)
 
@@ -103,6 +112,9 @@ func TestVersionManager(t *testing.T) {
 	getVersionable(resource, version, action, bucketID, "1/buckets/2/keys", targetPmts, t)
 	targetPmts = map[string]string{"bucketId": "buckets", "keyId": "keys"}
 	getVersionable(resource, version, action, bucketID, "buckets/buckets/keys/keys", targetPmts, t)
+	// A static path param is more specific than a named one
+	targetPmts = map[string]string{"keyId": "1"}
+	getVersionable(resource, version, action, bucketID, "1/values", targetPmts, t)
 
 	// Invalid URLs
 	getVersionableExpectingErrors(resource, version, action, bucketID, "buckets/key", t)
@@ -110,6 +122,23 @@ func TestVersionManager(t *testing.T) {
 	getVersionableExpectingErrors(resource, version, action, bucketID, "buckets/1/2", t)
 	getVersionableExpectingErrors(resource, version, action, bucketID, "1/buckets/2", t)
 	getVersionableExpectingErrors(resource, version, action, bucketID, "bucket/1/keys/2", t)
+
+	// Every registered path is listed, in order
+	list, err := List()
+	if err != nil {
+		t.Fatal("Failed to list versionables - " + err.Error())
+	}
+	targetPaths := []string{"", version1.Path, version2.Path, version3.Path, version5.Path, version4.Path, version6.Path}
+	paths := []string{}
+	for _, registered := range list {
+		paths = append(paths, registered.Version.Path)
+		if registered.Version.GetBasicVersion() != version1.GetBasicVersion() {
+			t.Error("Listed version mismatch for this path - " + registered.Version.Path)
+		}
+	}
+	if !reflect.DeepEqual(paths, targetPaths) {
+		t.Errorf("Listed paths mismatch, expected %v got %v", targetPaths, paths)
+	}
 }
 
 func getVersionable(resource string, version string, action string, bucketID string, path string, targetPmts map[string]string, t *testing.T) {
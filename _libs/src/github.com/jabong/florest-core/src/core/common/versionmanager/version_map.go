@@ -3,6 +3,7 @@ package versionmanager
 import (
 	"errors"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"sort"
 	"strings"
 )
 
@@ -98,27 +99,73 @@ func (param *Param) GetVersionable(pathParams string,
 
 func (param *Param) getVersionableObj(pathParams []string,
 	parameters *map[string]string) (Versionable, *ratelimiter.RateLimiter, error) {
-	tempParams := pathParams
-	notFoundError := errors.New("Versionable not found in version manager")
-	for _, pathParam := range pathParams {
-		tempParams = tempParams[1:]
-		if param.pathParams[pathParam] != nil {
-			param = param.pathParams[pathParam]
-		} else {
-			for key, namedParam := range param.namedParams {
-				versionable, rl, err := namedParam.getVersionableObj(tempParams, parameters)
-				if err == nil {
-					(*parameters)[key] = pathParam
-					return versionable, rl, nil
-				}
-			}
-			return nil, nil, notFoundError
+	matched, named := param.match(pathParams)
+	if matched == nil || matched.versionable == nil {
+		return nil, nil, errors.New("Versionable not found in version manager")
+	}
+	for key, value := range named {
+		(*parameters)[key] = value
+	}
+	return matched.versionable, matched.rateLimiter, nil
+}
+
+/*
+match walks the path, a static path param is preferred over a named param. When several named
+params match, the path binding the fewest named params wins, so that "{id}/history" is chosen
+over "{type}/{id}" for "1/history" whatever the map order
+*/
+func (param *Param) match(pathParams []string) (*Param, map[string]string) {
+	if len(pathParams) == 0 {
+		if param.versionable == nil {
+			return nil, nil
 		}
+		return param, map[string]string{}
+	}
+	pathParam := pathParams[0]
+	if param.pathParams[pathParam] != nil {
+		return param.pathParams[pathParam].match(pathParams[1:])
+	}
+	keys := make([]string, 0, len(param.namedParams))
+	for key := range param.namedParams {
+		keys = append(keys, key)
 	}
-	if param.versionable == nil {
-		return nil, nil, notFoundError
+	sort.Strings(keys)
+	var best *Param
+	var bestNamed map[string]string
+	for _, key := range keys {
+		matched, named := param.namedParams[key].match(pathParams[1:])
+		if matched == nil || (best != nil && len(named) >= len(bestNamed)) {
+			continue
+		}
+		named[key] = pathParam
+		best, bestNamed = matched, named
 	}
-	return param.versionable, param.rateLimiter, nil
+	return best, bestNamed
 }
 
 type VersionMap map[BasicVersion]*Param
+
+/*
+walk calls fn with the path, named params in between { and }, of every versionable registered
+under the param
+*/
+func (param *Param) walk(path []string, fn func(path string, versionable Versionable)) {
+	if param.versionable != nil {
+		fn(strings.Join(path, "/"), param.versionable)
+	}
+	for _, key := range sortedKeys(param.pathParams) {
+		param.pathParams[key].walk(append(path[:len(path):len(path)], key), fn)
+	}
+	for _, key := range sortedKeys(param.namedParams) {
+		param.namedParams[key].walk(append(path[:len(path):len(path)], "{"+key+"}"), fn)
+	}
+}
+
+func sortedKeys(params map[string]*Param) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,9 +3,11 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/imdario/mergo"
 	"github.com/jabong/florest-core/src/common/config"
 	"github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/components/cache"
@@ -17,18 +19,17 @@ var configKey string
 var initialized = false
 
 type DynamicConfigManager struct {
-	applicationConfig interface{}
 }
 
 /**
- * Initialize dynamic config manager and start the refresh timer
+ * Initialize dynamic config manager and start the refresh timer. The dynamic config is applied
+ * to the global application config, applicationConfig is kept for compatibility
  */
 func (dcm *DynamicConfigManager) Initialize(applicationConfig interface{}, cacheKey string) {
 	//Check if the Dynamic config is already initialized
 	if initialized {
 		return
 	}
-	dcm.applicationConfig = applicationConfig
 	initialized = true
 	dynamicConfObj := config.GlobalAppConfig.DynamicConfig
 	if !dynamicConfObj.Active {
@@ -59,38 +60,160 @@ func (dcm *DynamicConfigManager) refreshConfigAtEveryInterval() {
 }
 
 /**
- * Gets the updated config from Blitz and merges with the current application config
+ * Gets the updated config from Blitz and applies it to the current application config
  */
 func (dcm *DynamicConfigManager) refreshConfig() {
 	logger.Info("Refreshing the config. Time now : " + time.Now().String())
 	data, _ := cacheImpl.Get(configKey, true, true)
 	if data != nil && data.Value != nil {
-		var raw json.RawMessage
-		newAppConfig := dcm.applicationConfig
-		if dataValue, ok := data.Value.(string); ok {
-			raw = json.RawMessage(dataValue)
-		} else {
+		dataValue, ok := data.Value.(string)
+		if !ok {
 			logger.Warning(fmt.Sprintf("Error - cannot convert to type string"))
 			return
 		}
-		dataInBytes, err := json.Marshal(&raw)
-		if err != nil {
-			logger.Warning(fmt.Sprintf("Incorrect Json. Error - %s", err))
-			return
-		}
-		err = json.Unmarshal(dataInBytes, newAppConfig)
-		if err != nil {
-			logger.Warning(fmt.Sprintf("Incorrect Json. Error - %s", err))
-			return
-		}
-		configCopy := config.GlobalAppConfig.ApplicationConfig
-		// Mergo : Library to merge structs and maps in Golang.
-		err = mergo.MergeWithOverwrite(config.GlobalAppConfig.ApplicationConfig, newAppConfig)
-		if err != nil {
-			logger.Warning(fmt.Sprintf("Failed to merge the application config. Error - %s", err))
-			config.GlobalAppConfig.ApplicationConfig = configCopy
+		if err := applyDynamicConfig([]byte(dataValue)); err != nil {
+			logger.Warning(fmt.Sprintf("Dynamic config not applied. Error - %s", err))
 		}
 	} else {
 		logger.Warning("Could not find the dynamic config - key : " + configKey + " in central config cache")
 	}
 }
+
+/**
+ * Merges the dynamic config with a copy of the application config, fields missing from the dynamic config keep
+ * their value. The copy is validated by the registered validator and replaces the application config only when
+ * valid, the registered listener is then told of the new config
+ */
+func applyDynamicConfig(data []byte) error {
+	current := config.GlobalAppConfig.ApplicationConfig
+	newAppConfig, err := mergeConfig(current, data)
+	if err != nil {
+		return err
+	}
+	if dynamicConfigValidator != nil {
+		if err = dynamicConfigValidator(newAppConfig); err != nil {
+			return fmt.Errorf("Invalid config - %s", err)
+		}
+	}
+	changes, err := diffConfig(current, newAppConfig)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, change := range changes {
+		logger.Info("Dynamic config changed : " + change)
+	}
+	config.GlobalAppConfig.ApplicationConfig = newAppConfig
+	if dynamicConfigListener != nil {
+		dynamicConfigListener(newAppConfig)
+	}
+	return nil
+}
+
+//mergeConfig unmarshals the dynamic config on a copy of the application config, which has to be a pointer
+func mergeConfig(current interface{}, data []byte) (interface{}, error) {
+	t := reflect.TypeOf(current)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("Application config %T is not a pointer", current)
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	newAppConfig := reflect.New(t.Elem()).Interface()
+	if err = json.Unmarshal(currentJSON, newAppConfig); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, newAppConfig); err != nil {
+		return nil, fmt.Errorf("Incorrect Json - %s", err)
+	}
+	return newAppConfig, nil
+}
+
+//diffConfig lists the changed values of the config as "path: old -> new", sorted by path.
+//The values of secrets, the fields named like a password, token or secret, are not logged
+func diffConfig(oldConfig interface{}, newConfig interface{}) ([]string, error) {
+	oldValues, err := flattenConfig(oldConfig)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := flattenConfig(newConfig)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]bool)
+	for path := range oldValues {
+		paths[path] = true
+	}
+	for path := range newValues {
+		paths[path] = true
+	}
+	var changes []string
+	for path := range paths {
+		oldValue, oldFound := oldValues[path]
+		newValue, newFound := newValues[path]
+		if oldFound && newFound && oldValue == newValue {
+			continue
+		}
+		if !oldFound {
+			oldValue = "<none>"
+		}
+		if !newFound {
+			newValue = "<none>"
+		}
+		if isSecretPath(path) {
+			changes = append(changes, path+": <redacted>")
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", path, oldValue, newValue))
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+//flattenConfig maps the path of every value of the config, like MySql.Master.Host, to the value in JSON
+func flattenConfig(conf interface{}) (map[string]string, error) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	flattenValue("", value, values)
+	return values, nil
+}
+
+func flattenValue(path string, value interface{}, values map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenValue(childPath, child, values)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, values)
+		}
+	default:
+		data, _ := json.Marshal(v)
+		values[path] = string(data)
+	}
+}
+
+func isSecretPath(path string) bool {
+	name := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, secret := range []string{"password", "token", "secret"} {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jabong/florest-core/src/common/config"
+)
+
+type testDynamicConfig struct {
+	Limit    int
+	Host     string
+	Password string
+	Tags     []string
+	Nested   *testNestedConfig
+}
+
+type testNestedConfig struct {
+	Size int
+}
+
+func setTestDynamicConfig(t *testing.T) *testDynamicConfig {
+	previous := config.GlobalAppConfig.ApplicationConfig
+	validator, listener := dynamicConfigValidator, dynamicConfigListener
+	t.Cleanup(func() {
+		config.GlobalAppConfig.ApplicationConfig = previous
+		dynamicConfigValidator, dynamicConfigListener = validator, listener
+	})
+	conf := &testDynamicConfig{Limit: 10, Host: "a", Password: "secret", Tags: []string{"x"}, Nested: &testNestedConfig{Size: 1}}
+	config.GlobalAppConfig.ApplicationConfig = conf
+	return conf
+}
+
+func TestApplyDynamicConfig(t *testing.T) {
+	conf := setTestDynamicConfig(t)
+	var applied interface{}
+	RegisterDynamicConfigValidator(nil)
+	RegisterDynamicConfigListener(func(applicationConfig interface{}) {
+		applied = applicationConfig
+	})
+
+	if err := applyDynamicConfig([]byte(`{"Limit": 20, "Nested": {"Size": 2}}`)); err != nil {
+		t.Fatalf("Dynamic config not applied: %v", err)
+	}
+	newConf, ok := config.GlobalAppConfig.ApplicationConfig.(*testDynamicConfig)
+	if !ok {
+		t.Fatalf("Application config has type %T", config.GlobalAppConfig.ApplicationConfig)
+	}
+	expected := &testDynamicConfig{Limit: 20, Host: "a", Password: "secret", Tags: []string{"x"}, Nested: &testNestedConfig{Size: 2}}
+	if !reflect.DeepEqual(newConf, expected) {
+		t.Errorf("Applied config %+v, expected %+v", newConf, expected)
+	}
+	if applied != config.GlobalAppConfig.ApplicationConfig {
+		t.Error("Listener not told of the applied config")
+	}
+	if conf.Limit != 10 || conf.Nested.Size != 1 {
+		t.Errorf("Previous config changed to %+v", conf)
+	}
+}
+
+func TestApplyDynamicConfigRejected(t *testing.T) {
+	conf := setTestDynamicConfig(t)
+	RegisterDynamicConfigValidator(func(applicationConfig interface{}) error {
+		if applicationConfig.(*testDynamicConfig).Limit > 50 {
+			return errors.New("Limit should be at most 50")
+		}
+		return nil
+	})
+	RegisterDynamicConfigListener(func(applicationConfig interface{}) {
+		t.Error("Listener told of a rejected config")
+	})
+
+	for _, data := range []string{`{"Limit": 100}`, `{"Limit": "x"}`, `not json`} {
+		if err := applyDynamicConfig([]byte(data)); err == nil {
+			t.Errorf("Dynamic config %s applied", data)
+		}
+		if config.GlobalAppConfig.ApplicationConfig != conf {
+			t.Errorf("Application config replaced by %s", data)
+		}
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	oldConf := &testDynamicConfig{Limit: 10, Host: "a", Password: "secret", Tags: []string{"x"}}
+	newConf := &testDynamicConfig{Limit: 10, Host: "b", Password: "other", Tags: []string{"x", "y"}, Nested: &testNestedConfig{Size: 2}}
+	changes, err := diffConfig(oldConf, newConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`Host: "a" -> "b"`,
+		`Nested.Size: <none> -> 2`,
+		`Nested: null -> <none>`,
+		`Password: <redacted>`,
+		`Tags[1]: <none> -> "y"`,
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Changes %q, expected %q", changes, expected)
+	}
+	if changes, _ = diffConfig(oldConf, oldConf); len(changes) != 0 {
+		t.Errorf("Changes %q of an unchanged config", changes)
+	}
+}
@@ -87,9 +87,31 @@ func (n *HTTPResponseCreator) Execute(data workflow.WorkFlowData) (workflow.Work
 	apiResponse, _ := r.(utilhttp.APIResponse)
 	apiResponse.HTTPStatus = appResponse.Status.HTTPStatusCode
 	apiResponse.Body = jsonBody
+	if appResponse.Status.Success {
+		//The api workflow may respond with a different success status, e.g. 304 Not Modified
+		if s, _ := data.IOData.Get(constants.ResponseHTTPStatus); s != nil {
+			if httpStatus, ok := s.(constants.HTTPCode); ok {
+				apiResponse.HTTPStatus = httpStatus
+				if !bodyAllowedForStatus(httpStatus) {
+					apiResponse.Body = nil
+				}
+			}
+		}
+	}
 	data.IOData.Set(constants.APIResponse, apiResponse)
 
 	logger.Info(fmt.Sprintln("exiting ", n.Name()), rc)
 
 	return data, nil
 }
+
+//bodyAllowedForStatus reports whether a response with the given status may have a body
+func bodyAllowedForStatus(status constants.HTTPCode) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == 204, status == 304:
+		return false
+	}
+	return true
+}
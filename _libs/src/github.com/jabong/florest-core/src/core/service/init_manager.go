@@ -10,6 +10,7 @@ import (
 	"github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/common/monitor"
 	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/common/tracer"
 	"github.com/jabong/florest-core/src/common/utils/http"
 	"github.com/jabong/florest-core/src/core/common/env"
 	"github.com/jabong/florest-core/src/core/common/orchestrator"
@@ -51,6 +52,9 @@ func (im InitManager) Execute() {
 	// initialize profiler
 	initProfiler()
 
+	// Initialize Tracer
+	InitTracer()
+
 	//Create the WorkFlows
 	InitVersionManager()
 
@@ -274,6 +278,17 @@ func InitMonitor() {
 	}
 }
 
+// InitTracer initializes the tracer, the spans are exported for the app if no service name is configured
+func InitTracer() {
+	conf := config.GlobalAppConfig.TracerConfig
+	if conf.ServiceName == "" {
+		conf.ServiceName = config.GlobalAppConfig.AppName
+	}
+	if err := tracer.Initialize(&conf); err != nil {
+		logger.Error(fmt.Sprintln("Could not initialise tracer ", err))
+	}
+}
+
 // InitHTTPPool: initialize http pool
 func InitHTTPPool() {
 	http.InitConnPool(&config.GlobalAppConfig.HTTPConfig)
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/jabong/florest-core/src/common/config"
 	"github.com/jabong/florest-core/src/common/constants"
+	utilhttp "github.com/jabong/florest-core/src/common/utils/http"
 )
 
 var apiList []APIInterface
@@ -10,6 +11,9 @@ var resourceBucketMapping map[string]string
 var apiCustomInitFunc func()
 var configEnvUpdateMap map[string]string
 var globalEnvUpdateMap map[string]string
+var dynamicConfigValidator func(applicationConfig interface{}) error
+var dynamicConfigListener func(applicationConfig interface{})
+var debugAuthorizer func(req *utilhttp.Request) bool
 
 func RegisterAPI(apiInstance APIInterface) {
 	apiList = append(apiList, apiInstance)
@@ -41,3 +45,19 @@ func RegisterConfigEnvUpdateMap(a map[string]string) {
 func RegisterGlobalEnvUpdateMap(a map[string]string) {
 	globalEnvUpdateMap = a
 }
+
+//RegisterDynamicConfigValidator registers the check of a dynamic config, a config it rejects is not applied
+func RegisterDynamicConfigValidator(f func(applicationConfig interface{}) error) {
+	dynamicConfigValidator = f
+}
+
+//RegisterDynamicConfigListener registers the function told of the application config applied from the dynamic config
+func RegisterDynamicConfigListener(f func(applicationConfig interface{})) {
+	dynamicConfigListener = f
+}
+
+//RegisterDebugAuthorizer registers the check of the requests asking for debug data, the debug data is only collected
+//for the requests it authorizes
+func RegisterDebugAuthorizer(f func(req *utilhttp.Request) bool) {
+	debugAuthorizer = f
+}
@@ -21,6 +21,11 @@ func getAppRequest(r *http.Request) (*utilhttp.Request, error) {
 	return &req, nil
 }
 
+//isDebugAuthorized tells if the debug data asked for can be returned, it can for all requests without a debug authorizer
+func isDebugAuthorized(appReq *utilhttp.Request) bool {
+	return debugAuthorizer == nil || debugAuthorizer(appReq)
+}
+
 func getBucketsMap(bucketsList string) map[string]string {
 	buckets := strings.Split(bucketsList, constants.FieldSeperator)
 	bucketMap := make(map[string]string, len(buckets))
@@ -56,7 +61,7 @@ func GetData(r *http.Request) (*orchestrator.WorkFlowData, error) {
 	serviceEcContext.Set(constants.HTTPReferrer, appReq.Headers.Referrer)
 	serviceEcContext.Set(utilhttp.CustomHeaderMap[utilhttp.RequestID], appReq.Headers.RequestID)
 	serviceEcContext.SetBuckets(getBucketsMap(appReq.Headers.BucketsList))
-	serviceEcContext.SetDebugFlag(appReq.Headers.Debug)
+	serviceEcContext.SetDebugFlag(appReq.Headers.Debug && isDebugAuthorized(appReq))
 
 	serviceEcContext.Set(constants.RequestContext,
 		utilhttp.RequestContext{
@@ -73,6 +78,8 @@ func GetData(r *http.Request) (*orchestrator.WorkFlowData, error) {
 
 	serviceWorkFlowData := new(orchestrator.WorkFlowData)
 	serviceWorkFlowData.Create(serviceInputOutput, serviceEcContext)
+	//The workflow stops when the client goes away
+	serviceWorkFlowData.SetContext(r.Context())
 
 	return serviceWorkFlowData, nil
 }
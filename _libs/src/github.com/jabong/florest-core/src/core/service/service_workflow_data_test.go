@@ -0,0 +1,40 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	utilhttp "github.com/jabong/florest-core/src/common/utils/http"
+)
+
+func getTestDebugMsgs(t *testing.T, token string) []interface{} {
+	req := httptest.NewRequest("GET", "/v1/test/", nil)
+	req.Header.Set(utilhttp.CustomHeaderMap[utilhttp.DebugFlag], "true")
+	req.Header.Set("X-Debug-Token", token)
+	data, err := GetData(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.ExecContext.SetDebugMsg("key", "value")
+	msgs, _ := data.ExecContext.GetDebugMsg()
+	return msgs
+}
+
+func TestDebugAuthorizer(t *testing.T) {
+	previous := debugAuthorizer
+	defer func() { debugAuthorizer = previous }()
+
+	RegisterDebugAuthorizer(nil)
+	if msgs := getTestDebugMsgs(t, ""); len(msgs) != 1 {
+		t.Errorf("Expected the debug data of all requests without a debug authorizer, got %v", msgs)
+	}
+	RegisterDebugAuthorizer(func(req *utilhttp.Request) bool {
+		return req.GetHeaderParameter("X-Debug-Token") == "token"
+	})
+	if msgs := getTestDebugMsgs(t, "wrong"); len(msgs) != 0 {
+		t.Errorf("Expected no debug data for an unauthorized request, got %v", msgs)
+	}
+	if msgs := getTestDebugMsgs(t, "token"); len(msgs) != 1 {
+		t.Errorf("Expected the debug data of an authorized request, got %v", msgs)
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"github.com/jabong/florest-core/src/common/config"
 	"github.com/jabong/florest-core/src/common/constants"
 	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/monitor"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/common/tracer"
 	utilhttp "github.com/jabong/florest-core/src/common/utils/http"
 	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
 	"github.com/jabong/florest-core/src/core/common/versionmanager"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -19,8 +22,17 @@ type Webserver struct {
 
 func (ws Webserver) ServiceHandler(w http.ResponseWriter, req *http.Request) {
 
+	//The request continues the trace of the caller, the spans of the workflow nodes and of the calls
+	//made while serving it are its children
+	ctx, span := tracer.StartSpan(tracer.Extract(req.Context(), req.Header), "HTTP "+req.Method, tracer.SpanKindServer)
+	defer span.End()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.target", req.URL.Path)
+	req = req.WithContext(ctx)
+
 	io, derr := GetData(req)
 	if derr != nil {
+		span.SetError(derr)
 		fmt.Fprintf(w, "Error %v", derr)
 		return
 	}
@@ -41,6 +53,10 @@ func (ws Webserver) ServiceHandler(w http.ResponseWriter, req *http.Request) {
 				w.Header().Set(key, val)
 			}
 			w.WriteHeader(int(v.HTTPStatus))
+			span.SetAttribute("http.status_code", strconv.Itoa(int(v.HTTPStatus)))
+			if v.HTTPStatus >= constants.HTTPStatusInternalServerErrorCode {
+				span.SetError(fmt.Errorf("Responded with status %d", v.HTTPStatus))
+			}
 			w.Write(v.Body)
 			return
 		}
@@ -76,6 +92,10 @@ func (ws Webserver) Start() {
 	}
 
 	http.HandleFunc("/", httpHandlerFunc)
+	//A monitor keeping the metrics, like Prometheus, serves them to be scraped
+	if metricsHandler, ok := monitor.GetInstance().(http.Handler); ok {
+		http.Handle(monitor.MetricsPath, metricsHandler)
+	}
 
 	//Start the web server
 	url := ":" + config.GlobalAppConfig.ServerPort
@@ -26,8 +26,20 @@ func registerAllApis() {
 	service.RegisterAPI(new(address.ListAddressAPI))
 	service.RegisterAPI(new(address.CreateAddressAPI))
 	service.RegisterAPI(new(address.UpdateAddressAPI))
+	service.RegisterAPI(new(address.PatchAddressAPI))
 	service.RegisterAPI(new(address.DeleteAddressAPI))
 	service.RegisterAPI(new(address.UpdateTypeAPI))
+	service.RegisterAPI(new(address.AddressHistoryAPI))
+	service.RegisterAPI(new(address.RestoreAddressAPI))
+	service.RegisterAPI(new(address.AddressPreferencesAPI))
+	service.RegisterAPI(new(address.UpdatePreferencesAPI))
+	service.RegisterAPI(new(address.VerifyPhoneAPI))
+	service.RegisterAPI(new(address.ConfirmPhoneAPI))
+	service.RegisterAPI(new(address.AdminListAddressAPI))
+	service.RegisterAPI(new(address.AdminViewAddressAPI))
+	service.RegisterAPI(new(address.AdminUpdateAddressAPI))
+	service.RegisterAPI(new(address.AdminDeleteAddressAPI))
+	service.RegisterAPI(new(address.AdminWorkflowsAPI))
 }
 
 func registerConfig() {
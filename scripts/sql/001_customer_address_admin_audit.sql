@@ -0,0 +1,14 @@
+-- Audit trail of the actions taken by support agents through the admin address APIs
+CREATE TABLE IF NOT EXISTS `customer_address_admin_audit` (
+  `id_customer_address_admin_audit` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `agent_id` varchar(64) NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `fk_customer` int(10) unsigned NOT NULL,
+  `fk_customer_address` int(10) unsigned DEFAULT NULL,
+  `reason` varchar(255) NOT NULL,
+  `request_id` varchar(64) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id_customer_address_admin_audit`),
+  KEY `idx_fk_customer` (`fk_customer`),
+  KEY `idx_agent_id` (`agent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
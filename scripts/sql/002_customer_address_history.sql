@@ -0,0 +1,17 @@
+-- Append-only history of the changes to customer_address. Rows are only ever inserted
+CREATE TABLE IF NOT EXISTS `customer_address_history` (
+  `id_customer_address_history` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `fk_customer_address` int(10) unsigned NOT NULL,
+  `fk_customer` int(10) unsigned NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `changes` text NOT NULL,
+  `actor_type` varchar(16) NOT NULL,
+  `actor_id` varchar(64) NOT NULL,
+  `app_id` varchar(64) DEFAULT NULL,
+  `session_id` varchar(128) DEFAULT NULL,
+  `request_id` varchar(64) DEFAULT NULL,
+  `transaction_id` varchar(64) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id_customer_address_history`),
+  KEY `idx_customer_address` (`fk_customer`, `fk_customer_address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,4 @@
+-- Deleted addresses are marked with deleted_at and purged once the restore window has passed
+ALTER TABLE `customer_address`
+  ADD COLUMN `deleted_at` datetime DEFAULT NULL,
+  ADD KEY `idx_deleted_at` (`deleted_at`);
@@ -0,0 +1,3 @@
+-- Every change of an address increments its version, which gives the entity tag used by If-Match and If-None-Match
+ALTER TABLE `customer_address`
+  ADD COLUMN `version` int(10) unsigned NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Addresses can be named by the user and typed as home, work or other. Office addresses become work addresses
+ALTER TABLE `customer_address`
+  ADD COLUMN `label` varchar(64) DEFAULT NULL,
+  ADD COLUMN `label_type` enum('home','work','other') NOT NULL DEFAULT 'home';
+
+UPDATE `customer_address` SET `label_type` = 'work' WHERE `address_type` = 1;
@@ -0,0 +1,7 @@
+-- Optional structured components of the address, address1/address2 stay the rendered lines read by v1 clients
+ALTER TABLE `customer_address`
+  ADD COLUMN `house_number` varchar(100) DEFAULT NULL,
+  ADD COLUMN `building` varchar(100) DEFAULT NULL,
+  ADD COLUMN `street` varchar(100) DEFAULT NULL,
+  ADD COLUMN `locality` varchar(100) DEFAULT NULL,
+  ADD COLUMN `landmark` varchar(100) DEFAULT NULL;
@@ -0,0 +1,10 @@
+-- Delivery preferences of an address, an address without a row takes the defaults
+CREATE TABLE IF NOT EXISTS `customer_address_preference` (
+  `fk_customer_address` int(10) unsigned NOT NULL,
+  `instructions` varchar(255) DEFAULT NULL,
+  `delivery_windows` varchar(255) DEFAULT NULL,
+  `weekend_delivery` tinyint(1) NOT NULL DEFAULT 1,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`fk_customer_address`),
+  CONSTRAINT `fk_customer_address_preference_address` FOREIGN KEY (`fk_customer_address`) REFERENCES `customer_address` (`id_customer_address`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,4 @@
+-- Receiver of the deliveries to an address when not the account holder, both stored encrypted
+ALTER TABLE `customer_address`
+  ADD COLUMN `receiver_name` varchar(255) DEFAULT NULL,
+  ADD COLUMN `receiver_phone` varchar(255) DEFAULT NULL;
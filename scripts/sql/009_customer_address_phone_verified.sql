@@ -0,0 +1,3 @@
+-- Set once the phone of an address is confirmed with a code sent to it, reset whenever the phone changes
+ALTER TABLE `customer_address`
+  ADD COLUMN `phone_verified` tinyint(1) NOT NULL DEFAULT 0;
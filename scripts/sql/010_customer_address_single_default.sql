@@ -0,0 +1,19 @@
+-- At most one default billing and one default shipping address per customer. Deleted addresses do not count,
+-- a restored address is never a default. Where a customer has several defaults the latest address keeps the flag.
+UPDATE customer_address ca
+  JOIN (SELECT fk_customer, MAX(id_customer_address) AS id_customer_address FROM customer_address
+        WHERE is_default_billing = 1 AND deleted_at IS NULL GROUP BY fk_customer) d ON d.fk_customer = ca.fk_customer
+  SET ca.is_default_billing = 0
+  WHERE ca.is_default_billing = 1 AND ca.deleted_at IS NULL AND ca.id_customer_address <> d.id_customer_address;
+
+UPDATE customer_address ca
+  JOIN (SELECT fk_customer, MAX(id_customer_address) AS id_customer_address FROM customer_address
+        WHERE is_default_shipping = 1 AND deleted_at IS NULL GROUP BY fk_customer) d ON d.fk_customer = ca.fk_customer
+  SET ca.is_default_shipping = 0
+  WHERE ca.is_default_shipping = 1 AND ca.deleted_at IS NULL AND ca.id_customer_address <> d.id_customer_address;
+
+ALTER TABLE `customer_address`
+  ADD COLUMN `default_billing_customer` int(10) unsigned AS (IF(`is_default_billing` = 1 AND `deleted_at` IS NULL, `fk_customer`, NULL)) STORED,
+  ADD COLUMN `default_shipping_customer` int(10) unsigned AS (IF(`is_default_shipping` = 1 AND `deleted_at` IS NULL, `fk_customer`, NULL)) STORED,
+  ADD UNIQUE KEY `uk_customer_address_default_billing` (`default_billing_customer`),
+  ADD UNIQUE KEY `uk_customer_address_default_shipping` (`default_shipping_customer`);
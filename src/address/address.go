@@ -7,6 +7,7 @@ import (
 	"github.com/jabong/florest-core/src/common/constants"
 	logger "github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
 	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
 )
 
@@ -53,20 +54,38 @@ func (a ListAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlo
 	var err error
 	var addressListResult *AddressResult
 	if addressType == "all" || addressType == "other" || addressType == "" {
-		addressListResult, err = GetAddressList(params, debugInfo)
+		addressListResult, err = GetAddressList(params.withContext(io.Context()), debugInfo)
 	} else {
-		addressListResult, err = GetAddressTypeList(params, debugInfo)
+		addressListResult, err = GetAddressTypeList(params.withContext(io.Context()), debugInfo)
 	}
 	if err != nil {
 		logger.Error("unable to get address")
 		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
 	}
 	addDebugContents(io, debugInfo)
+	if !params.QueryParams.IncludePreferences {
+		omitAddressPreferences(addressListResult.AddressList)
+	}
 	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressListResult)
 	if derr != nil {
 		logger.Error(fmt.Sprintf("Error in setting address list result to workflow data- %v", derr), rc)
 		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
 	}
 
+	//Let the client skip downloading a list it already has
+	etag := getAddressListETag(addressListResult.AddressList)
+	if params.QueryParams.IncludePreferences {
+		// The list with the preferences is another representation of the same addresses
+		etag = getAddressETag(etag, appconstant.INCLUDE_PREFERENCES)
+	}
+	io.IOData.Set(constants.ResponseHeaders, map[string]string{appconstant.ETAG: etag})
+	rp, _ := io.IOData.Get(constants.Request)
+	if appHTTPReq, ok := rp.(*utilHttp.Request); ok {
+		ifNoneMatch := appHTTPReq.GetHeaderParameter(appconstant.IF_NONE_MATCH)
+		if ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+			io.IOData.Set(constants.ResponseHTTPStatus, appconstant.HttpStatusNotModifiedCode)
+		}
+	}
+
 	return io, nil
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	constants "github.com/jabong/florest-core/src/common/constants"
 	logger "github.com/jabong/florest-core/src/common/logger"
@@ -49,11 +50,14 @@ func (a AddressValidator) Execute(io workflow.WorkFlowData) (workflow.WorkFlowDa
 	rp, _ := io.IOData.Get(constants.Request)
 	appHTTPReq, _ := rp.(*utilHttp.Request)
 	err := validateAddressParams(params, appHTTPReq.HTTPVerb, io)
+	if fe, ok := err.(*fieldError); ok {
+		countMetric(appconstant.VALIDATION_FAILURE_METRIC, 1, params, "field:"+fe.field, "rule:"+fe.rule)
+	}
 	if err != nil {
 		logger.Error("Address Validator:\tRequest params validation failed." + err.Error())
 		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
 	}
-	logger.Info(fmt.Sprintf("AddressValidator#Execute received the request params: %+v", params), rc)
+	logger.Info(fmt.Sprintf("AddressValidator#Execute received the request params: %s", redactPII(fmt.Sprintf("%+v", params))), rc)
 	return io, nil
 }
 
@@ -64,32 +68,46 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 	byteArr := []byte(bodyParam)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Invalid Body Param: %v", err), params.RequestContext)
-		return err
+		return newFieldError("body", appconstant.RULE_JSON, err.Error())
 	}
 
 	var r interface{}
 	err = json.Unmarshal(byteArr, &r)
 	if err != nil {
 		logger.Error(fmt.Sprintf("decodeJson error: %v", err), params.RequestContext)
-		return err
+		return newFieldError("body", appconstant.RULE_JSON, err.Error())
 	}
 
 	valMap, rOk := r.(map[string]interface{})
 	if !rOk {
 		err = errors.New("couldn't resolve json to map")
 		logger.Error(err.Error(), params.RequestContext)
-		return err
+		return newFieldError("body", appconstant.RULE_JSON, err.Error())
 	}
 
+	isPatch := httpVerb == utilHttp.PATCH
+	if isPatch {
+		params.QueryParams.PatchFields = make(map[string]bool)
+	}
 	address := AddressRequest{}
+	t := getTunables()
+	lenient := getExperimentParam(params, appconstant.EXPERIMENT_PARAM_VALIDATION, appconstant.VALIDATION_STRICT) == appconstant.VALIDATION_LENIENT
 	for key, value := range valMap {
+		// A null in a PATCH body clears the field
+		if isPatch && value == nil {
+			if !patchClearableFields[key] {
+				return newFieldError(key, appconstant.RULE_NULL, fmt.Sprintf("Field name '%s' can not be null", key))
+			}
+			params.QueryParams.PatchFields[key] = false
+			continue
+		}
 		switch key {
 		case appconstant.FIRST_NAME:
 			str, ok := value.(string)
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.FIRST_NAME)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.FIRST_NAME, appconstant.RULE_TYPE, msg)
 			}
 			address.FirstName = sanitize(str, true)
 		case appconstant.LAST_NAME:
@@ -97,7 +115,7 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.LAST_NAME)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.LAST_NAME, appconstant.RULE_TYPE, msg)
 			}
 			address.LastName = sanitize(str, true)
 		case appconstant.ADDRESS1:
@@ -105,7 +123,7 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.ADDRESS1)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.ADDRESS1, appconstant.RULE_TYPE, msg)
 			}
 			address.Address1 = sanitize(str, false)
 		case appconstant.ADDRESS2:
@@ -113,42 +131,67 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.ADDRESS2)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.ADDRESS2, appconstant.RULE_TYPE, msg)
 			}
 			address.Address2 = sanitize(str, false)
 		case appconstant.PHONE:
 			mobile, ok := value.(string)
+			mobile = normalizePhone(mobile, lenient, t.phoneLength)
 			if !ok || !isIntegral(mobile) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.PHONE)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.PHONE, appconstant.RULE_TYPE, msg)
 			}
-			validLen := 10
+			validLen := t.phoneLength
 			if len(mobile) != validLen {
 				msg := fmt.Sprintf("Invalid value for field '%s' - length should be %d", appconstant.PHONE, validLen)
-				return errors.New(msg)
+				return newFieldError(appconstant.PHONE, appconstant.RULE_LENGTH, msg)
 			}
 			address.Phone = mobile
 		case appconstant.ALTERNATE_PHONE:
 			altPh, ok := value.(string)
+			altPh = normalizePhone(altPh, lenient, t.phoneLength)
 			if !ok || !isIntegral(altPh) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.ALTERNATE_PHONE)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.ALTERNATE_PHONE, appconstant.RULE_TYPE, msg)
 			}
-			validLen := 10
+			validLen := t.phoneLength
 			// Can be empty or be 10 digits
 			if len(altPh) != validLen && len(altPh) != 0 {
 				msg := fmt.Sprintf("Invalid value for field '%s' - length should be %d", appconstant.ALTERNATE_PHONE, validLen)
-				return errors.New(msg)
+				return newFieldError(appconstant.ALTERNATE_PHONE, appconstant.RULE_LENGTH, msg)
 			}
 			address.AlternatePhone = altPh
+		case appconstant.RECEIVER_NAME:
+			str, ok := value.(string)
+			if !ok {
+				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.RECEIVER_NAME)
+				logger.Error(msg, params.RequestContext)
+				return newFieldError(appconstant.RECEIVER_NAME, appconstant.RULE_TYPE, msg)
+			}
+			address.ReceiverName = sanitize(str, true)
+		case appconstant.RECEIVER_PHONE:
+			receiverPh, ok := value.(string)
+			receiverPh = normalizePhone(receiverPh, lenient, t.phoneLength)
+			if !ok || !isIntegral(receiverPh) {
+				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.RECEIVER_PHONE)
+				logger.Error(msg, params.RequestContext)
+				return newFieldError(appconstant.RECEIVER_PHONE, appconstant.RULE_TYPE, msg)
+			}
+			validLen := t.phoneLength
+			// Can be empty or be 10 digits, like the alternate phone
+			if len(receiverPh) != validLen && len(receiverPh) != 0 {
+				msg := fmt.Sprintf("Invalid value for field '%s' - length should be %d", appconstant.RECEIVER_PHONE, validLen)
+				return newFieldError(appconstant.RECEIVER_PHONE, appconstant.RULE_LENGTH, msg)
+			}
+			address.ReceiverPhone = receiverPh
 		case appconstant.CITY:
 			str, ok := value.(string)
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.CITY)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.CITY, appconstant.RULE_TYPE, msg)
 			}
 			address.City = sanitize(str, false)
 		case appconstant.REGION:
@@ -156,7 +199,7 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok {
 				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.REGION)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.REGION, appconstant.RULE_TYPE, msg)
 			}
 			address.RegionName = str
 		case appconstant.ADDRESS_REGION:
@@ -164,7 +207,7 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok || !isIntegral(addressRegionID) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.ADDRESS_REGION)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.ADDRESS_REGION, appconstant.RULE_TYPE, msg)
 			}
 			address.AddressRegion = addressRegionID
 		case appconstant.POSTCODE:
@@ -172,12 +215,12 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok || !isIntegral(p) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.POSTCODE)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.POSTCODE, appconstant.RULE_TYPE, msg)
 			}
-			validLen := 6
+			validLen := t.postcodeLength
 			if len(p) != validLen {
 				msg := fmt.Sprintf("Invalid value for field '%s' - length should be %d", appconstant.POSTCODE, validLen)
-				return errors.New(msg)
+				return newFieldError(appconstant.POSTCODE, appconstant.RULE_LENGTH, msg)
 			}
 			address.PostCode = p
 		case appconstant.SMS_OPT:
@@ -185,12 +228,12 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok || !isIntegral(sms) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.SMS_OPT)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.SMS_OPT, appconstant.RULE_TYPE, msg)
 			}
 			str, _ := strconv.Atoi(sms)
 			if str != 0 && str != 1 {
 				logger.Error(fmt.Sprintf("Invalid Value in 'sms_opt' field - should be 0 or 1"), params.RequestContext)
-				return errors.New("Invalid Value in 'sms_opt' field - should be 0 or 1")
+				return newFieldError(appconstant.SMS_OPT, appconstant.RULE_VALUE, "Invalid Value in 'sms_opt' field - should be 0 or 1")
 			}
 			address.SmsOpt = sms
 		case appconstant.IS_OFFICE:
@@ -198,34 +241,99 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 			if !ok || !isIntegral(isOffice) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.IS_OFFICE)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.IS_OFFICE, appconstant.RULE_TYPE, msg)
 			}
 			str, _ := strconv.Atoi(isOffice)
 			if str != 0 && str != 1 {
 				logger.Error(fmt.Sprintf("Invalid Value in 'is_office' field - should be 0 or 1"), params.RequestContext)
-				return errors.New("Invalid Value in 'is_office' field - should be 0 or 1")
+				return newFieldError(appconstant.IS_OFFICE, appconstant.RULE_VALUE, "Invalid Value in 'is_office' field - should be 0 or 1")
 			}
 			address.IsOffice = isOffice
+		case appconstant.LABEL:
+			str, ok := value.(string)
+			if !ok {
+				msg := fmt.Sprintf("Field name '%s' is expected to be string type", appconstant.LABEL)
+				logger.Error(msg, params.RequestContext)
+				return newFieldError(appconstant.LABEL, appconstant.RULE_TYPE, msg)
+			}
+			address.Label = sanitizeLabel(str)
+			if len(address.Label) > t.maxLabelLength {
+				return newFieldError(appconstant.LABEL, appconstant.RULE_LENGTH, fmt.Sprintf("Invalid value for field '%s' - length should be at most %d", appconstant.LABEL, t.maxLabelLength))
+			}
+		case appconstant.LABEL_TYPE:
+			labelType, ok := value.(string)
+			if !ok || !isLabelType(labelType) {
+				msg := fmt.Sprintf("Field name '%s' should be one of %s, %s or %s", appconstant.LABEL_TYPE, appconstant.LABEL_TYPE_HOME, appconstant.LABEL_TYPE_WORK, appconstant.LABEL_TYPE_OTHER)
+				logger.Error(msg, params.RequestContext)
+				return newFieldError(appconstant.LABEL_TYPE, appconstant.RULE_VALUE, msg)
+			}
+			address.LabelType = labelType
+		case appconstant.HOUSE, appconstant.BUILDING, appconstant.STREET, appconstant.LOCALITY, appconstant.LANDMARK:
+			str, ok := value.(string)
+			if !ok {
+				msg := fmt.Sprintf("Field name '%s' is expected to be string type", key)
+				logger.Error(msg, params.RequestContext)
+				return newFieldError(key, appconstant.RULE_TYPE, msg)
+			}
+			str = sanitize(str, false)
+			if len(str) > t.maxComponentLength {
+				return newFieldError(key, appconstant.RULE_LENGTH, fmt.Sprintf("Invalid value for field '%s' - length should be at most %d", key, t.maxComponentLength))
+			}
+			setAddressComponent(&address, key, str)
 		case appconstant.COUNTRY:
 			country, ok := value.(string)
 			if !ok || !isIntegral(country) {
 				msg := fmt.Sprintf("Field name '%s' is expected to be integer type", appconstant.COUNTRY)
 				logger.Error(msg, params.RequestContext)
-				return errors.New(msg)
+				return newFieldError(appconstant.COUNTRY, appconstant.RULE_TYPE, msg)
 			}
 			address.Country = country
 		default:
 			break
 		}
+		if _, ok := patchColumns[key]; ok && isPatch {
+			params.QueryParams.PatchFields[key] = true
+		}
+	}
+	setLabelTypeAndOffice(&address, params.QueryParams.PatchFields)
+	// v1 clients read the address lines, render them from the components if they are not given.
+	// A PATCH renders them from the stored components
+	if !isPatch && address.Address1 == "" && hasAddressComponents(address) {
+		address1, address2 := formatAddressLines(address)
+		address.Address1 = address1
+		if address.Address2 == "" {
+			address.Address2 = address2
+		}
+	}
+	if isPatch {
+		if len(params.QueryParams.PatchFields) == 0 {
+			return newFieldError("body", appconstant.RULE_REQUIRED, "No address field to update")
+		}
+		required := map[string]string{
+			appconstant.FIRST_NAME:     address.FirstName,
+			appconstant.ADDRESS1:       address.Address1,
+			appconstant.CITY:           address.City,
+			appconstant.POSTCODE:       address.PostCode,
+			appconstant.ADDRESS_REGION: address.AddressRegion,
+		}
+		for key, val := range required {
+			if _, ok := params.QueryParams.PatchFields[key]; ok && val == "" {
+				return newFieldError(key, appconstant.RULE_REQUIRED, fmt.Sprintf("Field name '%s' can not be empty", key))
+			}
+		}
 	}
 	if httpVerb == "PUT" || httpVerb == "POST" {
 		// TODO: Tell what params are missing
-		if address.FirstName == "" ||
-			address.Address1 == "" ||
-			address.City == "" ||
-			address.PostCode == "" ||
-			address.AddressRegion == "" {
-			return fmt.Errorf("Required parameters are missing: %s=%s, %s=%s, %s=%s, %s=%s, %s=%s", appconstant.FIRST_NAME, address.FirstName, appconstant.ADDRESS1, address.Address1, appconstant.CITY, address.City, appconstant.POSTCODE, address.PostCode, appconstant.ADDRESS_REGION, address.AddressRegion)
+		if field := getMissingField(address); field != "" {
+			return newFieldError(field, appconstant.RULE_REQUIRED, fmt.Sprintf("Required parameters are missing: %s=%s, %s=%s, %s=%s, %s=%s, %s=%s", appconstant.FIRST_NAME, address.FirstName, appconstant.ADDRESS1, address.Address1, appconstant.CITY, address.City, appconstant.POSTCODE, address.PostCode, appconstant.ADDRESS_REGION, address.AddressRegion))
+		}
+		// A receiver can only be reached with both a name and a phone
+		if (address.ReceiverName == "") != (address.ReceiverPhone == "") {
+			field := appconstant.RECEIVER_NAME
+			if address.ReceiverPhone == "" {
+				field = appconstant.RECEIVER_PHONE
+			}
+			return newFieldError(field, appconstant.RULE_TOGETHER, fmt.Sprintf("%s and %s should be given together", appconstant.RECEIVER_NAME, appconstant.RECEIVER_PHONE))
 		}
 	}
 	params.QueryParams.Address = address
@@ -233,6 +341,88 @@ func validateAddressParams(params *RequestParams, httpVerb utilHttp.Method, io w
 	return nil
 }
 
+//fieldError is a field of the request failing a validation rule, the failures are counted by field and rule
+type fieldError struct {
+	field string
+	rule  string
+	msg   string
+}
+
+func (e *fieldError) Error() string {
+	return e.msg
+}
+
+func newFieldError(field string, rule string, msg string) error {
+	return &fieldError{field: field, rule: rule, msg: msg}
+}
+
+//getMissingField gets the first of the fields required to create or replace an address that is empty
+func getMissingField(address AddressRequest) string {
+	required := []struct {
+		field string
+		value string
+	}{
+		{appconstant.FIRST_NAME, address.FirstName},
+		{appconstant.ADDRESS1, address.Address1},
+		{appconstant.CITY, address.City},
+		{appconstant.POSTCODE, address.PostCode},
+		{appconstant.ADDRESS_REGION, address.AddressRegion},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return r.field
+		}
+	}
+	return ""
+}
+
+func isLabelType(val string) bool {
+	return val == appconstant.LABEL_TYPE_HOME || val == appconstant.LABEL_TYPE_WORK || val == appconstant.LABEL_TYPE_OTHER
+}
+
+//setLabelTypeAndOffice keeps the label type and the office flag consistent for clients which send only
+//one of them. A work address is an office address. Without either the address is a home address
+func setLabelTypeAndOffice(address *AddressRequest, patch map[string]bool) {
+	switch {
+	case address.LabelType != "" && address.IsOffice == "":
+		address.IsOffice = "0"
+		if address.LabelType == appconstant.LABEL_TYPE_WORK {
+			address.IsOffice = "1"
+		}
+	case address.LabelType == "" && address.IsOffice != "":
+		address.LabelType = appconstant.LABEL_TYPE_HOME
+		if address.IsOffice == "1" {
+			address.LabelType = appconstant.LABEL_TYPE_WORK
+		}
+	case patch == nil && address.LabelType == "":
+		address.LabelType = appconstant.LABEL_TYPE_HOME
+	}
+	// A PATCH of either field writes both
+	if patch != nil {
+		if patch[appconstant.LABEL_TYPE] || patch[appconstant.IS_OFFICE] {
+			patch[appconstant.LABEL_TYPE] = true
+			patch[appconstant.IS_OFFICE] = true
+		}
+	}
+}
+
+//normalizePhone drops the separators, the country code and the leading zero of a phone under lenient
+//validation, a strictly validated phone is the digits alone
+func normalizePhone(phone string, lenient bool, phoneLength int) string {
+	if !lenient {
+		return phone
+	}
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(phone)
+	phone = strings.TrimPrefix(phone, "+")
+	if len(phone) == phoneLength+len(appconstant.PHONE_COUNTRY_CODE) && strings.HasPrefix(phone, appconstant.PHONE_COUNTRY_CODE) {
+		return phone[len(appconstant.PHONE_COUNTRY_CODE):]
+	}
+	if len(phone) == phoneLength+1 && strings.HasPrefix(phone, "0") {
+		return phone[1:]
+	}
+	return phone
+}
+
 func isIntegral(val string) bool {
 	if val == "" {
 		return true
@@ -11,6 +11,7 @@ import (
 	"github.com/jabong/florest-core/src/common/profiler"
 	"github.com/jabong/florest-core/src/components/cache"
 	"github.com/jabong/florest-core/src/components/sqldb"
+	"github.com/jabong/florest-core/src/core/service"
 )
 
 var encryptServiceObj *EncryptionService
@@ -18,6 +19,8 @@ var encryptServiceObj *EncryptionService
 //Initialise initialises Address Accessor
 func Initialise() {
 	var err error
+	initRedaction()
+	service.RegisterDebugAuthorizer(isDebugAuthorized)
 	appConfig, _ := appconfig.GetAddressServiceConfig()
 	encryptServiceObj, err = InitEncryptionService(appConfig.EncryptionServiceConfig.Host, appConfig.EncryptionServiceConfig.ReqTimeout)
 	if err != nil {
@@ -29,6 +32,16 @@ func Initialise() {
 	if err = cache.Set(cache.Redis, appConfig.Cache.Redis, new(cache.RedisClientAdapter)); err != nil {
 		logger.Error(err)
 	}
+	smsSenderName := ""
+	if appConfig.PhoneVerification != nil {
+		smsSenderName = appConfig.PhoneVerification.Sender
+	}
+	smsSenderObj, err = initSmsSender(smsSenderName)
+	if err != nil {
+		panic("Failed to initialise SMS Sender " + err.Error())
+	}
+	initDynamicConfig(appConfig)
+	startPurgeJob()
 	logger.Info(fmt.Sprintf("Address Service Accessor Initialize"))
 }
 
@@ -53,34 +66,39 @@ func GetAddressList(params *RequestParams, debugInfo *Debug) (*AddressResult, er
 	if params.QueryParams.AddressType != "" {
 		addressType = params.QueryParams.AddressType
 	}
-	addressResult, orderList, err = getAddressListFromCache(userID, params.QueryParams, debugInfo)
+	addressResult, orderList, err = getAddressListFromCache(userID, params, debugInfo)
 	if len(addressResult) == 0 || addressResult == nil || err != nil {
 		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "GetAddressList.Err", Value: err.Error()})
 		logger.Error(fmt.Sprintf("Error in getting addresslist from cache. Error::" + err.Error()))
+		countMetric(appconstant.DB_FALLBACK_METRIC, 1, params, "operation:list")
 		addressResult, orderList, err = getAddressList(params, "", debugInfo)
 		if err != nil {
 			logger.Error(fmt.Sprintf("error in getting the address list - %v", err))
 			return a, err
 		}
 	}
+	if params.QueryParams.Region != "" {
+		// The region is filtered by name as well, the names are set by the AddressJoiner only after the filter
+		regions, rerr := getRegionMetadata(params.Context(), debugInfo)
+		if rerr != nil {
+			logger.Error(fmt.Sprintf("GetAddressList: Could not get the regions - %v", rerr))
+		}
+		setRegionNames(addressResult, regions)
+	}
 	start := params.QueryParams.Offset
 	end := params.QueryParams.Offset + params.QueryParams.Limit
 	addressFiltered := make(map[string]*AddressResponse, 0)
 	orderFiltered := make([]string, 0)
-	if addressType == "all" || params.QueryParams.AddressType == "" {
-		if params.QueryParams.Limit != 0 {
-			addressFiltered = addressResult //[start:end]
-			orderFiltered = orderList
+	for _, k := range orderList {
+		v, ok := addressResult[k]
+		if !ok || !matchesAddressFilters(v, params.QueryParams) {
+			continue
 		}
-	} else {
-		for k, v := range addressResult {
-			if addressType == "other" {
-				if v.IsDefaultShipping == "0" && v.IsDefaultBilling == "0" {
-					addressFiltered[k] = v
-					orderFiltered = append(orderFiltered, k)
-				}
-			}
+		if addressType == appconstant.OTHER && (v.IsDefaultShipping != "0" || v.IsDefaultBilling != "0") {
+			continue
 		}
+		addressFiltered[k] = v
+		orderFiltered = append(orderFiltered, k)
 	}
 	if end > len(addressFiltered) {
 		end = len(addressFiltered)
@@ -98,7 +116,7 @@ func GetAddressList(params *RequestParams, debugInfo *Debug) (*AddressResult, er
 		temp[orderFiltered[i]] = addressFiltered[orderFiltered[i]]
 	}
 	a.AddressList = temp
-	a.Summary = AddressDetails{Count: len(temp), Type: addressType}
+	a.Summary = AddressDetails{Count: len(temp), Total: len(orderFiltered), Type: addressType}
 	return a, nil
 }
 
@@ -124,14 +142,21 @@ func UpdateAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, err
 	a := new(AddressResult)
 	// Set as default shipping address
 	if params.QueryParams.Default == 1 {
+		// The cache is rebuilt from the database once the default is set, the update has to be committed before
+		if err := updateAddressInDb(params, debugInfo); err != nil {
+			return a, err
+		}
 		_, err := UpdateType(params, debugInfo)
 		if err != nil {
 			logger.Error(fmt.Sprintf("There is some error occured while updating the type %v", err), rc)
 			return a, errors.New("Some error occurred while setting the default address")
 		}
+		return a, nil
 	}
 
-	go updateAddressInDb(params, debugInfo)
+	if err := updateAddressInDb(params, debugInfo); err != nil {
+		return a, err
+	}
 	return a, nil
 }
 
@@ -141,22 +166,14 @@ func UpdateType(params *RequestParams, debugInfo *Debug) (*AddressResult, error)
 	defer func() {
 		prof.EndProfileWithMetric([]string{"address-address_accessor-UpdateType"})
 	}()
-	cacheErr := updateTypeInCache(params, debugInfo)
-	if cacheErr != nil {
-		cacheKey := GetAddressListCacheKey(params.RequestContext.UserID)
-		err := invalidateCache(cacheKey)
-		logger.Error(fmt.Sprintf("UpdateAddress: Error while invalidating the cache key %s %v", cacheKey, err))
-		cacheKey = GetAddressOrderCacheKey(params.RequestContext.UserID)
-		err = invalidateCache(cacheKey)
-		logger.Error(fmt.Sprintf("UpdateAddress: Error while invalidating the cache key %s %v", cacheKey, err))
-	}
-	e := make(chan error, 0)
-	go updateType(params, debugInfo, e)
-
-	err := <-e
+	err := updateType(params, debugInfo)
 	if err != nil {
 		return nil, err
 	}
+	// The cached list is rebuilt from the committed state, every address whose default changed is replaced
+	if _, _, err = getAddressList(params, "", debugInfo); err != nil {
+		invalidateAddressCache(params.RequestContext.UserID)
+	}
 	a := new(AddressResult)
 	return a, nil
 }
@@ -171,10 +188,9 @@ func AddAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, error)
 
 	rc := params.RequestContext
 	userId := rc.UserID
-	addressData := params.QueryParams.Address
 
-	isFirst, _ := isFirstAddress(userId, debugInfo)
-	lastInsertedId, err := addAddress(userId, addressData, debugInfo)
+	isFirst, _ := isFirstAddress(userId, params, debugInfo)
+	lastInsertedId, err := addAddress(params, debugInfo)
 	params.QueryParams.AddressId = int(lastInsertedId)
 
 	if err != nil {
@@ -207,6 +223,9 @@ func DeleteAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, err
 	defer func() {
 		prof.EndProfileWithMetric([]string{"address-address_accessor-DeleteAddress"})
 	}()
+	if params.QueryParams.Reassign {
+		return deleteAndReassignDefaults(params, debugInfo)
+	}
 	a := new(AddressResult)
 	flag, err1 := checkDefaultAddress(params, debugInfo)
 	if err1 != nil {
@@ -240,6 +259,24 @@ func DeleteAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, err
 	}
 }
 
+//deleteAndReassignDefaults deletes an address, moving its defaults to the successor, and returns the defaults after
+func deleteAndReassignDefaults(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	rc := params.RequestContext
+	a := new(AddressResult)
+	err := deleteAddressWithReassign(params, debugInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The cached list is rebuilt from the committed state, there is none left if the only address was deleted
+	invalidateAddressCache(rc.UserID)
+	addressList, _, err := getAddressList(params, "", debugInfo)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Some error occured while getting the default addresses after deleting the address"), rc)
+	}
+	a.Defaults = getDefaultAddresses(addressList)
+	return a, nil
+}
+
 func GetAddressTypeList(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
 	prof := profiler.NewProfiler()
 	prof.StartProfile("address-address_accessor-GetAddressTypeList")
@@ -251,10 +288,11 @@ func GetAddressTypeList(params *RequestParams, debugInfo *Debug) (*AddressResult
 	userID := rc.UserID
 	a := new(AddressResult)
 	addressType := params.QueryParams.AddressType
-	addressResult, _, err := getAddressListFromCache(userID, params.QueryParams, debugInfo)
+	addressResult, _, err := getAddressListFromCache(userID, params, debugInfo)
 	if len(addressResult) == 0 || addressResult == nil || err != nil {
 		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "GetAddressList.Err", Value: err.Error()})
 		logger.Error(fmt.Sprintf("Error in getting addresslist from cache. Error::" + err.Error()))
+		countMetric(appconstant.DB_FALLBACK_METRIC, 1, params, "operation:type_list")
 		addressResult, _, err = getAddressList(params, "", debugInfo)
 		if err != nil {
 			logger.Error(fmt.Sprintf("error in getting the address list - %v", err))
@@ -273,9 +311,10 @@ func GetAddressTypeList(params *RequestParams, debugInfo *Debug) (*AddressResult
 	}
 
 	a.AddressList = addressResult[index]
-	if addressResult[index] != nil {
-		a.Summary = AddressDetails{Count: 1, Type: addressType}
+	if addressResult[index] != nil && matchesAddressFilters(addressResult[index], params.QueryParams) {
+		a.Summary = AddressDetails{Count: 1, Total: 1, Type: addressType}
 	} else {
+		a.AddressList = nil
 		a.Summary = AddressDetails{Count: 0, Type: addressType}
 	}
 	return a, nil
@@ -287,9 +326,10 @@ func checkDefaultAddress(params *RequestParams, debugInfo *Debug) (int, error) {
 	userID := params.RequestContext.UserID
 	addressID := params.QueryParams.AddressId
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "CheckDefaultAddress", Value: "CheckDefaultAddress Execute"})
-	addressResult, _, err := getAddressListFromCache(userID, params.QueryParams, debugInfo)
+	addressResult, _, err := getAddressListFromCache(userID, params, debugInfo)
 	if err != nil || len(addressResult) == 0 {
 		logger.Info(fmt.Sprintf("Address not found in cache for addressID: %d", params.QueryParams.AddressId))
+		countMetric(appconstant.DB_FALLBACK_METRIC, 1, params, "operation:check_default")
 		val, err1 := checkDefaultAddressInDB(addressID, userID, debugInfo)
 		if err1 != nil {
 			return 0, err1
@@ -306,3 +346,168 @@ func checkDefaultAddress(params *RequestParams, debugInfo *Debug) (int, error) {
 	}
 
 }
+
+//GetAddressHistory gets the change history of an address of the user
+func GetAddressHistory(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-GetAddressHistory")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-GetAddressHistory"})
+	}()
+
+	a := new(AddressResult)
+	history, err := getAddressHistory(params, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("error in getting the address history - %v", err), params.RequestContext)
+		return a, err
+	}
+	a.AddressList = history
+	a.Summary = AddressDetails{Count: len(history)}
+	return a, nil
+}
+
+//RestoreAddress restores an address deleted within the restore window
+func RestoreAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-RestoreAddress")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-RestoreAddress"})
+	}()
+
+	rc := params.RequestContext
+	a := new(AddressResult)
+	err := restoreAddress(params, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	invalidateAddressCache(rc.UserID)
+	addressResult, _, err := getAddressList(params, strconv.Itoa(params.QueryParams.AddressId), debugInfo)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Some error occured while getting address details after restoring the address"), rc)
+	}
+	a.AddressList = addressResult
+	return a, nil
+}
+
+//PatchAddress updates only the fields present in the PATCH body and returns the updated address
+func PatchAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-PatchAddress")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-PatchAddress"})
+	}()
+
+	a := new(AddressResult)
+	err := patchAddressInDb(params, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	addressResult := refreshAddressInCache(params, strconv.Itoa(params.QueryParams.AddressId), debugInfo)
+	a.AddressList = addressResult
+	a.Summary = AddressDetails{Count: len(addressResult), Type: appconstant.ALL}
+	return a, nil
+}
+
+//GetAddressPreferences gets the delivery preferences of an address of the user
+func GetAddressPreferences(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-GetAddressPreferences")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-GetAddressPreferences"})
+	}()
+
+	a := new(AddressResult)
+	addressList, _, err := getUserAddresses(params, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("error in getting the address list - %v", err), params.RequestContext)
+		return a, err
+	}
+	address, ok := addressList[strconv.Itoa(params.QueryParams.AddressId)]
+	if !ok || address == nil {
+		return a, errAddressNotFound
+	}
+	a.AddressList = address.Preferences
+	a.Summary = AddressDetails{Count: 1}
+	return a, nil
+}
+
+//UpdateAddressPreferences replaces the delivery preferences of an address and returns them
+func UpdateAddressPreferences(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-UpdateAddressPreferences")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-UpdateAddressPreferences"})
+	}()
+
+	a := new(AddressResult)
+	err := saveAddressPreferencesInDb(params, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	refreshAddressInCache(params, strconv.Itoa(params.QueryParams.AddressId), debugInfo)
+	a.AddressList = params.QueryParams.Preferences
+	a.Summary = AddressDetails{Count: 1}
+	return a, nil
+}
+
+//SendPhoneVerification sends a code to verify the phone of an address of the user
+func SendPhoneVerification(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-SendPhoneVerification")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-SendPhoneVerification"})
+	}()
+
+	a := new(AddressResult)
+	verification, err := sendPhoneVerification(params, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	a.AddressList = verification
+	a.Summary = AddressDetails{Count: 1}
+	return a, nil
+}
+
+//ConfirmPhoneVerification checks the code sent for an address and returns the address with its phone verified
+func ConfirmPhoneVerification(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_accessor-ConfirmPhoneVerification")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_accessor-ConfirmPhoneVerification"})
+	}()
+
+	a := new(AddressResult)
+	err := confirmPhoneVerification(params, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	addressResult := refreshAddressInCache(params, strconv.Itoa(params.QueryParams.AddressId), debugInfo)
+	a.AddressList = addressResult
+	a.Summary = AddressDetails{Count: len(addressResult), Type: appconstant.ALL}
+	return a, nil
+}
+
+//refreshAddressInCache reads an address again after it was written and replaces it in the cached list of the
+//user. The cached list is invalidated if the address can not be replaced. The address read is returned
+func refreshAddressInCache(params *RequestParams, addressID string, debugInfo *Debug) map[string]*AddressResponse {
+	addressResult, _, err := getAddressList(params, addressID, debugInfo)
+	address := addressResult[addressID]
+	if err == nil && address != nil {
+		err = replaceAddressInCache(params, address, debugInfo)
+	}
+	if err != nil || address == nil {
+		logger.Warning(fmt.Sprintf("Could not update address %s in cache, %v", addressID, err), params.RequestContext)
+		invalidateAddressCache(params.RequestContext.UserID)
+	}
+	return addressResult
+}
+
+//invalidateAddressCache removes the cached address list and order of a user, they are rebuilt from the database
+//on the next read
+func invalidateAddressCache(userID string) {
+	for _, cacheKey := range []string{GetAddressListCacheKey(userID), GetAddressOrderCacheKey(userID)} {
+		if err := invalidateCache(cacheKey); err != nil {
+			logger.Error(fmt.Sprintf("Error while invalidating the cache key %s, %v", cacheKey, err))
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package address
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+)
+
+//decryptionLatency is the latency of a call to the decryption service
+const decryptionLatency = 2 * time.Millisecond
+
+//startTestDecryptionService serves the decryption service, returning every value as decrypted
+func startTestDecryptionService(b *testing.B) func() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(decryptionLatency)
+		data := []interface{}{}
+		for _, v := range r.URL.Query()["q"] {
+			data = append(data, "decrypted-"+v)
+		}
+		body, _ := json.Marshal(utilHttp.Response{Data: data})
+		w.Write(body)
+	}))
+	previous := encryptServiceObj
+	service, err := InitEncryptionService(server.URL, "1000")
+	if err != nil {
+		b.Fatal(err)
+	}
+	encryptServiceObj = service
+	return func() {
+		encryptServiceObj = previous
+		server.Close()
+	}
+}
+
+func getBenchmarkEncryptedFields() []EncryptedFields {
+	ef := []EncryptedFields{}
+	for i := 0; i < 5; i++ {
+		id := strconv.Itoa(i)
+		ef = append(ef, EncryptedFields{Id: id, EncryptedPhone: "phone" + id, EncryptedAlternatePhone: "alt" + id,
+			EncryptedReceiverName: "name" + id, EncryptedReceiverPhone: "receiver" + id})
+	}
+	return ef
+}
+
+//BenchmarkDecryptSequentially decrypts the phones, alternate phones and receivers one call after another,
+//as the list did before the calls were made concurrent
+func BenchmarkDecryptSequentially(b *testing.B) {
+	defer startTestDecryptionService(b)()
+	ef := getBenchmarkEncryptedFields()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var phones, altPhones []string
+		for _, v := range ef {
+			phones = append(phones, v.EncryptedPhone)
+			altPhones = append(altPhones, v.EncryptedAlternatePhone)
+		}
+		receivers, _ := getEncryptedReceivers(ef)
+		debug := new(Debug)
+		Decrypt(phones, debug)
+		Decrypt(altPhones, debug)
+		Decrypt(receivers, debug)
+	}
+}
+
+//BenchmarkDecryptEncryptedFields decrypts the phones, alternate phones and receivers concurrently
+func BenchmarkDecryptEncryptedFields(b *testing.B) {
+	defer startTestDecryptionService(b)()
+	ef := getBenchmarkEncryptedFields()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decryptEncryptedFields(ef, new(RequestParams), new(Debug)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
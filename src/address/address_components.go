@@ -0,0 +1,101 @@
+package address
+
+import (
+	"common/appconstant"
+	"strings"
+)
+
+//addressComponentFields are the structured components of an address, in the order they are rendered
+var addressComponentFields = []string{
+	appconstant.HOUSE,
+	appconstant.BUILDING,
+	appconstant.STREET,
+	appconstant.LOCALITY,
+	appconstant.LANDMARK,
+}
+
+//addressComponentColumns maps the structured components to their customer_address column
+var addressComponentColumns = map[string]string{
+	appconstant.HOUSE:    "house_number",
+	appconstant.BUILDING: "building",
+	appconstant.STREET:   "street",
+	appconstant.LOCALITY: "locality",
+	appconstant.LANDMARK: "landmark",
+}
+
+//getAddressComponent returns the value of a structured component of the address
+func getAddressComponent(a AddressRequest, field string) string {
+	switch field {
+	case appconstant.HOUSE:
+		return a.House
+	case appconstant.BUILDING:
+		return a.Building
+	case appconstant.STREET:
+		return a.Street
+	case appconstant.LOCALITY:
+		return a.Locality
+	case appconstant.LANDMARK:
+		return a.Landmark
+	}
+	return ""
+}
+
+//setAddressComponent sets the value of a structured component of the address
+func setAddressComponent(a *AddressRequest, field string, value string) {
+	switch field {
+	case appconstant.HOUSE:
+		a.House = value
+	case appconstant.BUILDING:
+		a.Building = value
+	case appconstant.STREET:
+		a.Street = value
+	case appconstant.LOCALITY:
+		a.Locality = value
+	case appconstant.LANDMARK:
+		a.Landmark = value
+	}
+}
+
+//hasAddressComponents reports if any structured component of the address is set
+func hasAddressComponents(a AddressRequest) bool {
+	for _, field := range addressComponentFields {
+		if getAddressComponent(a, field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+//formatAddressLines renders the structured components into the free-text lines read by v1 clients:
+//house, building and street on the first line, locality and landmark on the second
+func formatAddressLines(a AddressRequest) (address1 string, address2 string) {
+	landmark := ""
+	if a.Landmark != "" {
+		landmark = appconstant.LANDMARK_PREFIX + a.Landmark
+	}
+	address1 = joinNonEmpty(", ", a.House, a.Building, a.Street)
+	address2 = joinNonEmpty(", ", a.Locality, landmark)
+	if address1 == "" {
+		// The first line is required
+		address1, address2 = address2, ""
+	}
+	return address1, address2
+}
+
+//getComponentValue returns the component to store, NULL if it is not given
+func getComponentValue(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,77 @@
+package address
+
+import (
+	"common/appconstant"
+
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+var _ = gk.Describe("Address components", func() {
+	gk.It("should render the components into the address lines", func() {
+		tests := []struct {
+			address  AddressRequest
+			address1 string
+			address2 string
+		}{
+			{AddressRequest{}, "", ""},
+			{AddressRequest{House: "12", Building: "Sunrise Apartments", Street: "MG Road", Locality: "Camp", Landmark: "City Mall"},
+				"12, Sunrise Apartments, MG Road", "Camp, Near City Mall"},
+			{AddressRequest{House: "12", Street: "MG Road"}, "12, MG Road", ""},
+			{AddressRequest{Building: "Sunrise Apartments", Landmark: "City Mall"}, "Sunrise Apartments", "Near City Mall"},
+			// The first line is required, the second line takes its place
+			{AddressRequest{Locality: "Camp", Landmark: "City Mall"}, "Camp, Near City Mall", ""},
+			{AddressRequest{Landmark: "City Mall"}, "Near City Mall", ""},
+		}
+		for _, test := range tests {
+			address1, address2 := formatAddressLines(test.address)
+			gm.Expect(address1).To(gm.Equal(test.address1))
+			gm.Expect(address2).To(gm.Equal(test.address2))
+		}
+	})
+
+	gk.It("should merge the patched components with the stored ones", func() {
+		stored := map[string]*string{
+			"house_number": stringPtr("12"),
+			"building":     stringPtr("Sunrise Apartments"),
+			"street":       stringPtr("MG Road"),
+			"locality":     nil,
+		}
+		tests := []struct {
+			name   string
+			patch  AddressRequest
+			fields map[string]bool
+			stored map[string]*string
+			merged AddressRequest
+		}{
+			{"nothing patched", AddressRequest{}, map[string]bool{}, stored,
+				AddressRequest{House: "12", Building: "Sunrise Apartments", Street: "MG Road"}},
+			{"component set", AddressRequest{House: "14", Locality: "Camp"},
+				map[string]bool{appconstant.HOUSE: true, appconstant.LOCALITY: true}, stored,
+				AddressRequest{House: "14", Building: "Sunrise Apartments", Street: "MG Road", Locality: "Camp"}},
+			{"component cleared", AddressRequest{}, map[string]bool{appconstant.BUILDING: false}, stored,
+				AddressRequest{House: "12", Street: "MG Road"}},
+			// Only the components are merged
+			{"other fields", AddressRequest{FirstName: "Asha", Landmark: "City Mall"},
+				map[string]bool{appconstant.FIRST_NAME: true}, stored,
+				AddressRequest{House: "12", Building: "Sunrise Apartments", Street: "MG Road"}},
+			{"nothing stored", AddressRequest{Street: "MG Road"}, map[string]bool{appconstant.STREET: true}, nil,
+				AddressRequest{Street: "MG Road"}},
+		}
+		for _, test := range tests {
+			merged := mergeAddressComponents(test.patch, test.fields, test.stored)
+			gm.Expect(merged).To(gm.Equal(test.merged), test.name)
+		}
+	})
+
+	gk.It("should set the components of a patch to their value, or NULL", func() {
+		a := AddressRequest{House: "12", Landmark: ""}
+		gm.Expect(getPatchValue(a, appconstant.HOUSE, true)).To(gm.Equal("12"))
+		gm.Expect(getPatchValue(a, appconstant.HOUSE, false)).To(gm.BeNil())
+		gm.Expect(getPatchValue(a, appconstant.LANDMARK, true)).To(gm.BeNil())
+	})
+})
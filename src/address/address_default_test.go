@@ -0,0 +1,216 @@
+package address
+
+import (
+	"common/appconstant"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//recordingDB is a database that records the statements run in it. The lock of the addresses of the
+//customer reads the rows given, the other queries read no row
+type recordingDB struct {
+	mutex      sync.Mutex
+	rows       [][]driver.Value
+	failOn     string
+	statements []string
+	args       [][]driver.Value
+}
+
+var recordingDBs sync.Map
+
+func init() {
+	sql.Register("recording", recordingDriver{})
+}
+
+//beginRecordingTx begins a transaction in the database
+func beginRecordingTx(db *recordingDB) *sql.Tx {
+	name := fmt.Sprintf("%p", db)
+	recordingDBs.Store(name, db)
+	conn, err := sql.Open("recording", name)
+	gm.Expect(err).To(gm.BeNil())
+	txObj, err := conn.Begin()
+	gm.Expect(err).To(gm.BeNil())
+	return txObj
+}
+
+func (db *recordingDB) record(query string, args []driver.Value) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.statements = append(db.statements, query)
+	db.args = append(db.args, args)
+	if db.failOn != "" && strings.HasPrefix(query, db.failOn) {
+		return errors.New("lock wait timeout")
+	}
+	return nil
+}
+
+//updates gets the updates run, with the address they updated
+func (db *recordingDB) updates() []string {
+	updates := []string{}
+	for i, statement := range db.statements {
+		if strings.HasPrefix(statement, "UPDATE") {
+			updates = append(updates, fmt.Sprintf("%s %v", statement, db.args[i][0]))
+		}
+	}
+	return updates
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	db, _ := recordingDBs.Load(name)
+	return &recordingConn{db.(*recordingDB)}, nil
+}
+
+type recordingConn struct {
+	db *recordingDB
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{db: c.db, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *recordingConn) Commit() error { return nil }
+
+func (c *recordingConn) Rollback() error { return nil }
+
+type recordingStmt struct {
+	db    *recordingDB
+	query string
+}
+
+func (s *recordingStmt) Close() error { return nil }
+
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.db.record(s.query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.db.record(s.query, args); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(s.query, "SELECT id_customer_address,") {
+		return &recordingRows{rows: s.db.rows}, nil
+	}
+	return &recordingRows{}, nil
+}
+
+type recordingRows struct {
+	rows [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return []string{"id_customer_address", "is_default"} }
+
+func (r *recordingRows) Close() error { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var _ = gk.Describe("Default address", func() {
+	// The addresses of the customer, 2 is the default
+	addresses := [][]driver.Value{{int64(1), int64(0)}, {int64(2), int64(1)}, {int64(3), int64(0)}}
+	params := &RequestParams{}
+	params.RequestContext.UserID = "1773895"
+
+	gk.It("should get the other addresses that are default", func() {
+		tests := []struct {
+			name      string
+			rows      [][]driver.Value
+			addressId string
+			previous  []string
+			found     bool
+		}{
+			{"another default", addresses, "3", []string{"2"}, true},
+			{"already the default", addresses, "2", []string{}, true},
+			{"no default", [][]driver.Value{{int64(1), int64(0)}, {int64(3), int64(0)}}, "3", []string{}, true},
+			// Every previous default is reset, even if there is more than one
+			{"several defaults", [][]driver.Value{{int64(1), int64(1)}, {int64(2), int64(1)}, {int64(3), int64(0)}}, "3", []string{"1", "2"}, true},
+			{"unknown address", addresses, "4", []string{"2"}, false},
+			{"no addresses", nil, "3", []string{}, false},
+		}
+		for _, test := range tests {
+			db := &recordingDB{rows: test.rows}
+			previous, found, err := getDefaultAddressIds(beginRecordingTx(db), "SELECT id_customer_address, is_default_billing FROM customer_address", "1773895", test.addressId)
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(previous).To(gm.Equal(test.previous), test.name)
+			gm.Expect(found).To(gm.Equal(test.found), test.name)
+			gm.Expect(db.args[0]).To(gm.Equal([]driver.Value{"1773895"}), test.name)
+		}
+	})
+
+	gk.It("should reset the previous defaults before setting the new default", func() {
+		tests := []struct {
+			name        string
+			rows        [][]driver.Value
+			addressType string
+			addressId   string
+			ifMatch     string
+			updates     []string
+			err         error
+		}{
+			{"another default", addresses, appconstant.BILLING, "3", "", []string{
+				"UPDATE customer_address SET is_default_billing = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 2",
+				"UPDATE customer_address SET is_default_billing = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 3",
+			}, nil},
+			{"several defaults", [][]driver.Value{{int64(1), int64(1)}, {int64(2), int64(1)}, {int64(3), int64(0)}}, appconstant.SHIPPING, "3", "", []string{
+				"UPDATE customer_address SET is_default_shipping = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 1",
+				"UPDATE customer_address SET is_default_shipping = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 2",
+				"UPDATE customer_address SET is_default_shipping = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 3",
+			}, nil},
+			{"already the default", addresses, appconstant.BILLING, "2", "", []string{
+				"UPDATE customer_address SET is_default_billing = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 2",
+			}, nil},
+			// The If-Match applies to the address set as the default only
+			{"if match", addresses, appconstant.BILLING, "3", "4", []string{
+				"UPDATE customer_address SET is_default_billing = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? 2",
+				"UPDATE customer_address SET is_default_billing = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? AND version = ? 3",
+			}, nil},
+			{"unknown address", addresses, appconstant.BILLING, "4", "", []string{}, errAddressNotFound},
+		}
+		for _, test := range tests {
+			db := &recordingDB{rows: test.rows}
+			p := *params
+			p.QueryParams.IfMatchVersion = test.ifMatch
+			err := setAddressDefault(beginRecordingTx(db), &p, getDefaultColumn(test.addressType), test.addressId, &Debug{})
+			if test.err != nil {
+				gm.Expect(err).To(gm.Equal(test.err), test.name)
+			} else {
+				gm.Expect(err).To(gm.BeNil(), test.name)
+			}
+			gm.Expect(db.updates()).To(gm.Equal(test.updates), test.name)
+			// The addresses of the customer are locked first
+			gm.Expect(db.statements[0]).To(gm.HaveSuffix("FOR UPDATE"), test.name)
+		}
+	})
+
+	gk.It("should not set the new default when a previous default can not be reset", func() {
+		db := &recordingDB{rows: addresses, failOn: "UPDATE customer_address SET is_default_billing = 0"}
+		err := setAddressDefault(beginRecordingTx(db), params, getDefaultColumn(appconstant.BILLING), "3", &Debug{})
+		gm.Expect(err).NotTo(gm.BeNil())
+		gm.Expect(db.updates()).To(gm.HaveLen(1))
+		gm.Expect(db.updates()[0]).To(gm.ContainSubstring("is_default_billing = 0"))
+	})
+})
@@ -0,0 +1,131 @@
+package address
+
+import (
+	"common/appconstant"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+//errAddressChanged is returned when the address was changed after the If-Match of the request was checked
+var errAddressChanged = errors.New("The address has been changed since it was last read")
+
+//getAddressETag returns the entity tag of a version of an address
+func getAddressETag(addressID string, version string) string {
+	sum := sha1.Sum([]byte(addressID + ":" + version))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+//setAddressVersion sets the version of an address along with its entity tag
+func setAddressVersion(address *AddressResponse, version string) {
+	address.Version = version
+	address.ETag = getAddressETag(address.Id, version)
+}
+
+//bumpAddressVersion mirrors in a cached address the version increment done by an update in the db
+func bumpAddressVersion(address *AddressResponse) {
+	version, _ := strconv.Atoi(address.Version)
+	setAddressVersion(address, strconv.Itoa(version+1))
+}
+
+//getAddressListETag returns the entity tag of a list result, which changes whenever an address in
+//the list is added, removed or updated
+func getAddressListETag(addressList interface{}) string {
+	var addresses []*AddressResponse
+	switch v := addressList.(type) {
+	case map[string]*AddressResponse:
+		for _, address := range v {
+			addresses = append(addresses, address)
+		}
+	case *AddressResponse:
+		if v != nil {
+			addresses = append(addresses, v)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Id < addresses[j].Id
+	})
+	h := sha1.New()
+	for _, address := range addresses {
+		fmt.Fprintf(h, "%s:%s;", address.Id, address.Version)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+//etagMatches reports if etag is one of the entity tags listed in an If-Match or If-None-Match header.
+//Weak tags are compared by their value and * matches any entity tag
+func etagMatches(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+//getAddressVersion gets the current version of an address of the user from the db. An empty version
+//is returned if the address does not exist
+func getAddressVersion(params *RequestParams, debug *Debug) (string, error) {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return "", err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AddressETag#getAddressVersion")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AddressETag#getAddressVersion"})
+	}()
+
+	rc := params.RequestContext
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	query := `SELECT version FROM customer_address WHERE id_customer_address = ? AND fk_customer = ? AND deleted_at IS NULL`
+	debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "getAddressVersion:Sql", Value: query + "id_customer_address: " + addressID + "fk_customer: " + rc.UserID})
+	rows, derr := db.Query(query, addressID, rc.UserID)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while getting address version |%s|%s|%s", appconstant.MYSQL_ERROR, derr.Error(), "customer_address"), rc)
+		return "", derr
+	}
+	defer rows.Close()
+	var version string
+	if rows.Next() {
+		if serr := rows.Scan(&version); serr != nil {
+			logger.Error(fmt.Sprintf("Mysql Row Error while getting address version |%s|%s|%s", appconstant.MYSQL_ERROR, serr.Error(), "customer_address"), rc)
+			return "", serr
+		}
+	}
+	return version, nil
+}
+
+//versionCondition returns the condition restricting an update to the version matched by the If-Match of
+//the request, along with its argument. Both are empty if the request had no If-Match
+func versionCondition(params *RequestParams) (string, []interface{}) {
+	if params.QueryParams.IfMatchVersion == "" {
+		return "", nil
+	}
+	return ` AND version = ?`, []interface{}{params.QueryParams.IfMatchVersion}
+}
+
+//checkVersionUpdated returns errAddressChanged if an update restricted by versionCondition changed no row
+func checkVersionUpdated(params *RequestParams, res sql.Result) error {
+	if params.QueryParams.IfMatchVersion == "" {
+		return nil
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errAddressChanged
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package address
+
+import (
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//rowsResult is the result of an update changing rows rows
+type rowsResult int64
+
+func (r rowsResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (r rowsResult) RowsAffected() (int64, error) {
+	return int64(r), nil
+}
+
+var _ = gk.Describe("Address ETag", func() {
+	gk.It("should change the entity tag with the address and its version", func() {
+		etag := getAddressETag("35495082", "3")
+		gm.Expect(etag).To(gm.MatchRegexp(`^"[0-9a-f]{40}"$`))
+		gm.Expect(getAddressETag("35495082", "3")).To(gm.Equal(etag))
+		gm.Expect(getAddressETag("35495082", "4")).NotTo(gm.Equal(etag))
+		gm.Expect(getAddressETag("35495058", "3")).NotTo(gm.Equal(etag))
+	})
+
+	gk.It("should match If-Match against the entity tag", func() {
+		etag := getAddressETag("35495082", "3")
+		tests := []struct {
+			header  string
+			matches bool
+		}{
+			{etag, true},
+			{"W/" + etag, true},
+			{"*", true},
+			{`"other", ` + etag, true},
+			{getAddressETag("35495082", "2"), false},
+			{`"other"`, false},
+			{"", false},
+		}
+		for _, test := range tests {
+			gm.Expect(etagMatches(test.header, etag)).To(gm.Equal(test.matches), test.header)
+		}
+	})
+
+	gk.It("should change the entity tag of a list when an address changes", func() {
+		list := map[string]*AddressResponse{
+			"1": {Id: "1", Version: "1"},
+			"2": {Id: "2", Version: "5"},
+		}
+		etag := getAddressListETag(list)
+		gm.Expect(getAddressListETag(map[string]*AddressResponse{"2": list["2"], "1": list["1"]})).To(gm.Equal(etag))
+
+		bumpAddressVersion(list["2"])
+		gm.Expect(list["2"].Version).To(gm.Equal("6"))
+		gm.Expect(list["2"].ETag).To(gm.Equal(getAddressETag("2", "6")))
+		bumped := getAddressListETag(list)
+		gm.Expect(bumped).NotTo(gm.Equal(etag))
+
+		delete(list, "1")
+		gm.Expect(getAddressListETag(list)).NotTo(gm.Equal(bumped))
+		gm.Expect(getAddressListETag(list["2"])).To(gm.Equal(getAddressListETag(list)))
+	})
+
+	gk.It("should write an update only on the version If-Match matched", func() {
+		tests := []struct {
+			version string
+			rows    rowsResult
+			err     error
+		}{
+			{"", 0, nil},
+			{"", 1, nil},
+			{"3", 1, nil},
+			{"3", 0, errAddressChanged},
+		}
+		for _, test := range tests {
+			params := &RequestParams{}
+			params.QueryParams.IfMatchVersion = test.version
+			condition, args := versionCondition(params)
+			if test.version == "" {
+				gm.Expect(condition).To(gm.BeEmpty())
+				gm.Expect(args).To(gm.BeEmpty())
+			} else {
+				gm.Expect(condition).To(gm.Equal(" AND version = ?"))
+				gm.Expect(args).To(gm.Equal([]interface{}{test.version}))
+			}
+			if err := checkVersionUpdated(params, test.rows); test.err == nil {
+				gm.Expect(err).To(gm.BeNil())
+			} else {
+				gm.Expect(err).To(gm.Equal(test.err))
+			}
+		}
+	})
+})
@@ -0,0 +1,26 @@
+package address
+
+import (
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AddressFetchFork starts the independent reads of a list or detail request on their own paths
+type AddressFetchFork struct {
+	id string
+}
+
+func (n *AddressFetchFork) SetID(id string) {
+	n.id = id
+}
+
+func (n AddressFetchFork) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n AddressFetchFork) Name() string {
+	return "AddressFetchFork"
+}
+
+func (n AddressFetchFork) Fork(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	return io, nil
+}
@@ -0,0 +1,36 @@
+package address
+
+import (
+	"strconv"
+	"strings"
+)
+
+//matchesAddressFilters reports if an address passes the filters of the address list. City and
+//region, by name or id, are compared ignoring case and q is searched in the label, name and address lines
+func matchesAddressFilters(address *AddressResponse, q QueryParams) bool {
+	if q.Postcode != 0 && address.PostCode != strconv.Itoa(q.Postcode) {
+		return false
+	}
+	if q.City != "" && !strings.EqualFold(address.City, q.City) {
+		return false
+	}
+	if q.Region != "" && !strings.EqualFold(address.RegionName, q.Region) && address.AddressRegion != q.Region {
+		return false
+	}
+	if q.Country != "" && address.Country != q.Country {
+		return false
+	}
+	if q.IsOffice != "" && address.IsOffice != q.IsOffice {
+		return false
+	}
+	if q.LabelType != "" && address.LabelType != q.LabelType {
+		return false
+	}
+	if q.Query != "" {
+		text := strings.ToLower(strings.Join([]string{address.Label, address.FirstName, address.LastName, address.Address1, address.Address2}, " "))
+		if !strings.Contains(text, strings.ToLower(q.Query)) {
+			return false
+		}
+	}
+	return true
+}
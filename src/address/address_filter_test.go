@@ -0,0 +1,57 @@
+package address
+
+import (
+	"common/appconstant"
+
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Address list filters", func() {
+	address := &AddressResponse{
+		FirstName:     "Asha",
+		LastName:      "Rao",
+		Address1:      "12 MG Road",
+		Address2:      "Near City Mall",
+		City:          "Pune",
+		PostCode:      "411001",
+		AddressRegion: "21",
+		RegionName:    "Maharashtra",
+		Country:       "99",
+		IsOffice:      "1",
+		Label:         "Office",
+		LabelType:     appconstant.LABEL_TYPE_WORK,
+	}
+
+	gk.It("should match an address passing every filter", func() {
+		tests := []struct {
+			name    string
+			q       QueryParams
+			matches bool
+		}{
+			{"no filter", QueryParams{}, true},
+			{"postcode", QueryParams{Postcode: 411001}, true},
+			{"other postcode", QueryParams{Postcode: 411002}, false},
+			{"city ignoring case", QueryParams{City: "pune"}, true},
+			{"other city", QueryParams{City: "Mumbai"}, false},
+			{"region name", QueryParams{Region: "MAHARASHTRA"}, true},
+			{"region id", QueryParams{Region: "21"}, true},
+			{"other region", QueryParams{Region: "Goa"}, false},
+			{"country", QueryParams{Country: "99"}, true},
+			{"other country", QueryParams{Country: "1"}, false},
+			{"address type", QueryParams{IsOffice: "1"}, true},
+			{"other address type", QueryParams{IsOffice: "0"}, false},
+			{"label type", QueryParams{LabelType: appconstant.LABEL_TYPE_WORK}, true},
+			{"other label type", QueryParams{LabelType: appconstant.LABEL_TYPE_HOME}, false},
+			{"q in label", QueryParams{Query: "office"}, true},
+			{"q in last name", QueryParams{Query: "RAO"}, true},
+			{"q in address line", QueryParams{Query: "city mall"}, true},
+			{"q in city only", QueryParams{Query: "Pune"}, false},
+			{"all filters", QueryParams{Postcode: 411001, City: "Pune", Region: "21", Query: "mg road"}, true},
+			{"one filter failing", QueryParams{Postcode: 411001, City: "Pune", Country: "1"}, false},
+		}
+		for _, test := range tests {
+			gm.Expect(matchesAddressFilters(address, test.q)).To(gm.Equal(test.matches), test.name)
+		}
+	})
+})
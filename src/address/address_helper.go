@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"sync"
 	"time"
 
 	logger "github.com/jabong/florest-core/src/common/logger"
@@ -27,9 +28,9 @@ type Debug struct {
 	MessageStack []DebugInfo
 }
 
-//addDebugContents add debug contents in the workflow data
+//addDebugContents add debug contents in the workflow data, with the personal data masked
 func addDebugContents(io workflow.WorkFlowData, debug *Debug) {
-	for k, v := range debug.MessageStack {
+	for k, v := range redactDebug(debug) {
 		io.ExecContext.SetDebugMsg(v.Key, v.Value)
 		logger.Info(fmt.Sprintf("params.DebugInfo %v- %v", k, v))
 	}
@@ -50,6 +51,18 @@ func urlEncode(reqURL string, data []string) string {
 
 //Decrypt to decrypt an encrypted string
 func Decrypt(encryptedData []string, debugInfo *Debug) []string {
+	data, _ := decrypt(encryptedData, nil, debugInfo)
+	return data
+}
+
+//decryptStats are the batches of a decryption and how many of them failed
+type decryptStats struct {
+	batches       int
+	failedBatches int
+}
+
+//decrypt decrypts in batches for the request, a failed batch leaves its values empty
+func decrypt(encryptedData []string, params *RequestParams, debugInfo *Debug) ([]string, decryptStats) {
 	p := profiler.NewProfiler()
 	p.StartProfile("AddressHelper#Decrypt")
 
@@ -61,46 +74,67 @@ func Decrypt(encryptedData []string, debugInfo *Debug) []string {
 		err               error
 		res               []byte
 		partialData, data []string
+		stats             decryptStats
 	)
-	batchSize := int(appconstant.BATCH_SIZE)
+	batchSize := getTunables().decryptBatchSize
 	length := len(encryptedData)
 
 	if length > batchSize {
 		modulo := length % batchSize
-		loop := math.Ceil(float64(length) / appconstant.BATCH_SIZE)
+		loop := math.Ceil(float64(length) / float64(batchSize))
 		loops := int(loop)
+		stats.batches = loops
 		for i := 0; i < loops; i++ {
 			if modulo != 0 && i == (loops-1) {
 				partialData = encryptedData[i*batchSize : (i*batchSize)+modulo]
 			} else {
 				partialData = encryptedData[i*batchSize : (i*batchSize)+batchSize]
 			}
-			res, err = encryptServiceObj.DecryptData(partialData, debugInfo)
+			res, err = encryptServiceObj.DecryptData(partialData, params, debugInfo)
 			if err != nil {
 				logger.Error("Decrypt: PartialResponse:: Data Decryption Error ", err.Error())
+				stats.failedBatches++
 				for k := 0; k < len(partialData); k++ {
 					data = append(data, "")
 				}
 			} else {
-				d, _ := getDataFromServiceResponse(res)
+				d, derr := getDataFromServiceResponse(res)
+				if derr != nil {
+					stats.failedBatches++
+				}
 				data = append(data, d...)
 			}
 
 		}
 	} else {
-		res, err = encryptServiceObj.DecryptData(encryptedData, debugInfo)
+		stats.batches = 1
+		res, err = encryptServiceObj.DecryptData(encryptedData, params, debugInfo)
 		if err != nil {
 			logger.Error("Decrypt: Data Decryption Error ", err.Error())
-			return data
+			stats.failedBatches++
+			return data, stats
 		}
 		data, err = getDataFromServiceResponse(res)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Decrypt: getDataFromServiceResponse() Error:: %+v", err))
-			return data
+			stats.failedBatches++
+			return data, stats
 		}
 	}
 
-	return data
+	return data, stats
+}
+
+//countDecryptionFailures counts the failed batches of a decryption, and the decryption as partial
+//if only some of its batches failed
+func countDecryptionFailures(params *RequestParams, stats decryptStats) {
+	if stats.failedBatches == 0 {
+		return
+	}
+	countMetric(appconstant.DECRYPTION_FAILURE_METRIC, int64(stats.failedBatches), params)
+	if stats.failedBatches < stats.batches {
+		countMetric(appconstant.DECRYPTION_PARTIAL_METRIC, 1, params)
+	}
 }
 
 //getDataFromServiceResponse to parse the encryption/decryption service response
@@ -146,8 +180,40 @@ func decryptEncryptedFields(ef []EncryptedFields, params *RequestParams, debug *
 		encryptedPhoneString = append(encryptedPhoneString, v.EncryptedPhone)
 		encryptedAltPhoneString = append(encryptedAltPhoneString, v.EncryptedAlternatePhone)
 	}
-	decryptedPhone := Decrypt(encryptedPhoneString, debug)
-	decryptedAltPhone := Decrypt(encryptedAltPhoneString, debug)
+	encryptedReceivers, receivers := getEncryptedReceivers(ef)
+
+	//The phones, alternate phones and receivers are decrypted concurrently, each call with its own debug
+	// messages so that they do not race on the debug of the request
+	var (
+		decryptedPhone, decryptedAltPhone, decryptedReceivers []string
+		phoneDebug, altPhoneDebug, receiverDebug              = new(Debug), new(Debug), new(Debug)
+		phoneStats, altPhoneStats, receiverStats              decryptStats
+		wg                                                    sync.WaitGroup
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		decryptedPhone, phoneStats = decrypt(encryptedPhoneString, params, phoneDebug)
+	}()
+	go func() {
+		defer wg.Done()
+		decryptedAltPhone, altPhoneStats = decrypt(encryptedAltPhoneString, params, altPhoneDebug)
+	}()
+	if len(encryptedReceivers) != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decryptedReceivers, receiverStats = decrypt(encryptedReceivers, params, receiverDebug)
+		}()
+	}
+	wg.Wait()
+	for _, d := range []*Debug{phoneDebug, altPhoneDebug, receiverDebug} {
+		debug.MessageStack = append(debug.MessageStack, d.MessageStack...)
+	}
+	for _, stats := range []decryptStats{phoneStats, altPhoneStats, receiverStats} {
+		countDecryptionFailures(params, stats)
+	}
+
 	res := make([]DecryptedFields, 0)
 	if len(decryptedPhone) > 0 {
 		for k, v := range ef {
@@ -157,7 +223,7 @@ func decryptEncryptedFields(ef []EncryptedFields, params *RequestParams, debug *
 				dp = ""
 			}
 
-			if decryptedAltPhone[k] != "" {
+			if k < len(decryptedAltPhone) && decryptedAltPhone[k] != "" {
 				dap = decryptedAltPhone[k]
 			} else {
 				dap = ""
@@ -168,9 +234,54 @@ func decryptEncryptedFields(ef []EncryptedFields, params *RequestParams, debug *
 	if len(res) == 0 {
 		return nil, errors.New("Error in Decrypting Encryption Fields")
 	}
+	setDecryptedReceivers(res, receivers, decryptedReceivers)
 	return res, nil
 }
 
+//receiverField is a receiver contact field of an address to decrypt
+type receiverField struct {
+	index   int
+	isPhone bool
+}
+
+//getEncryptedReceivers collects the receiver contacts to decrypt in one call, none when no address has a receiver
+func getEncryptedReceivers(ef []EncryptedFields) ([]string, []receiverField) {
+	var encryptedReceivers []string
+	var receivers []receiverField
+	for k, v := range ef {
+		if v.EncryptedReceiverName != "" {
+			encryptedReceivers = append(encryptedReceivers, v.EncryptedReceiverName)
+			receivers = append(receivers, receiverField{index: k})
+		}
+		if v.EncryptedReceiverPhone != "" {
+			encryptedReceivers = append(encryptedReceivers, v.EncryptedReceiverPhone)
+			receivers = append(receivers, receiverField{index: k, isPhone: true})
+		}
+	}
+	return encryptedReceivers, receivers
+}
+
+//setDecryptedReceivers sets the decrypted receiver contacts on the decrypted fields of their addresses
+func setDecryptedReceivers(res []DecryptedFields, receivers []receiverField, decryptedReceivers []string) {
+	if len(receivers) == 0 {
+		return
+	}
+	if len(decryptedReceivers) != len(receivers) {
+		logger.Error(fmt.Sprintf("setDecryptedReceivers: Could not decrypt the receivers, got %d of %d", len(decryptedReceivers), len(receivers)))
+		return
+	}
+	for k, v := range decryptedReceivers {
+		if v == "0" {
+			continue
+		}
+		if receivers[k].isPhone {
+			res[receivers[k].index].DecryptedReceiverPhone = v
+		} else {
+			res[receivers[k].index].DecryptedReceiverName = v
+		}
+	}
+}
+
 func mergeDecryptedFieldsWithAddressResult(ef []DecryptedFields, address *map[string]*AddressResponse) {
 	val := (*address)
 	for i := 0; i < len(ef); i++ {
@@ -180,6 +291,7 @@ func mergeDecryptedFieldsWithAddressResult(ef []DecryptedFields, address *map[st
 		} else {
 			val[ef[i].Id].AlternatePhone = ""
 		}
+		val[ef[i].Id].Receiver = newReceiverContact(ef[i].DecryptedReceiverName, ef[i].DecryptedReceiverPhone)
 	}
 }
 
@@ -188,8 +300,18 @@ func GetAddressListCacheKey(userID string) string {
 	return fmt.Sprintf(appconstant.ADDRESS_CACHE_KEY, userID)
 }
 
-//getAddressListFromCache get user's address list from cache
-func getAddressListFromCache(userId string, params QueryParams, debugInfo *Debug) (map[string]*AddressResponse, []string, error) {
+//getAddressListFromCache get user's address list from cache, counting the cache hits and misses
+func getAddressListFromCache(userId string, params *RequestParams, debugInfo *Debug) (map[string]*AddressResponse, []string, error) {
+	addressList, orderList, err := readAddressListFromCache(userId, params, debugInfo)
+	result := "hit"
+	if err != nil {
+		result = "miss"
+	}
+	countMetric(appconstant.CACHE_LOOKUP_METRIC, 1, params, "result:"+result)
+	return addressList, orderList, err
+}
+
+func readAddressListFromCache(userId string, params *RequestParams, debugInfo *Debug) (map[string]*AddressResponse, []string, error) {
 	p := profiler.NewProfiler()
 	p.StartProfile("AddressHelper#getAddressListFromCache")
 
@@ -201,7 +323,7 @@ func getAddressListFromCache(userId string, params QueryParams, debugInfo *Debug
 	orderList := make([]string, 0)
 	var address map[string]*AddressResponse
 	var order []string
-	cacheObj, errG := cache.Get(cache.Redis)
+	cacheObj, errG := cache.GetWithContext(params.Context(), cache.Redis)
 	if errG != nil {
 		msg := fmt.Sprintf("Redis Config Error - %v", errG)
 		logger.Error(msg)
@@ -234,6 +356,19 @@ func getAddressListFromCache(userId string, params QueryParams, debugInfo *Debug
 		return address, order, err
 	}
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "getAddressListFromCache:Result", Value: fmt.Sprintf("%+v", addressList)})
+	// Addresses cached before versions were introduced can not give a valid entity tag,
+	// nor do the ones cached before delivery preferences and phone verification have them
+	for _, a := range addressList {
+		if a.Version == "" {
+			return address, order, errors.New("Address list in cache has no versions")
+		}
+		if a.Preferences == nil {
+			return address, order, errors.New("Address list in cache has no delivery preferences")
+		}
+		if a.PhoneVerified == "" {
+			return address, order, errors.New("Address list in cache has no phone verification")
+		}
+	}
 
 	return addressList, orderList, nil
 }
@@ -282,7 +417,7 @@ func udpateAddressInCache(params *RequestParams, debugInfo *Debug) error {
 	userID := rc.UserID
 	address := params.QueryParams.Address
 
-	addressList, _, err := getAddressListFromCache(userID, params.QueryParams, debugInfo)
+	addressList, _, err := getAddressListFromCache(userID, params, debugInfo)
 
 	if err != nil {
 		logger.Error(fmt.Sprintf("Error while fetching address list from Cache"), rc)
@@ -292,13 +427,24 @@ func udpateAddressInCache(params *RequestParams, debugInfo *Debug) error {
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "udpateAddressInCache:id", Value: index})
 	addressList[index].IsOffice = params.QueryParams.Address.IsOffice
 	addressList[index].FirstName = address.FirstName
+	if addressList[index].Phone != address.Phone {
+		addressList[index].PhoneVerified = "0"
+	}
 	addressList[index].Phone = address.Phone
 	addressList[index].Address1 = address.Address1
 	addressList[index].City = address.City
 	addressList[index].AddressRegion = address.AddressRegion
 	addressList[index].PostCode = address.PostCode
 	addressList[index].SmsOpt = address.SmsOpt
+	addressList[index].LabelType = address.LabelType
+	addressList[index].House = address.House
+	addressList[index].Building = address.Building
+	addressList[index].Street = address.Street
+	addressList[index].Locality = address.Locality
+	addressList[index].Landmark = address.Landmark
+	addressList[index].Receiver = newReceiverContact(address.ReceiverName, address.ReceiverPhone)
 	addressList[index].UpdatedAt = time.Now().Format(appconstant.DATETIME_FORMAT)
+	bumpAddressVersion(addressList[index])
 
 	if address.Country != "" {
 		addressList[index].Country = address.Country
@@ -315,6 +461,9 @@ func udpateAddressInCache(params *RequestParams, debugInfo *Debug) error {
 	if address.AlternatePhone != "" {
 		addressList[index].AlternatePhone = address.AlternatePhone
 	}
+	if address.Label != "" {
+		addressList[index].Label = address.Label
+	}
 	err = saveDataInCache(userID, addressList)
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "saveDataInCache:cacheKey", Value: GetAddressListCacheKey(userID)})
 	if err != nil {
@@ -337,7 +486,7 @@ func updateAddressListInCache(params *RequestParams, addressID string, debug *De
 	userID := rc.UserID
 	address, _, _ := getAddressList(params, addressID, debug)
 
-	addressList, orderList, err := getAddressListFromCache(userID, params.QueryParams, debug)
+	addressList, orderList, err := getAddressListFromCache(userID, params, debug)
 	if err != nil {
 		logger.Error(fmt.Sprintf("updateAddressListInCache::Error while fetching address list from Cache"), rc)
 	}
@@ -372,7 +521,7 @@ func deleteAddressFromCache(params *RequestParams, debugInfo *Debug) (address ma
 	userId := rc.UserID
 	addressId := fmt.Sprintf("%d", params.QueryParams.AddressId)
 
-	addressList, orderList, err := getAddressListFromCache(userId, params.QueryParams, debugInfo)
+	addressList, orderList, err := getAddressListFromCache(userId, params, debugInfo)
 	if err != nil {
 		logger.Error(fmt.Sprintf("deleteAddressFromCache: Could not retrieve address list from Cache"), rc)
 		return address, errors.New("Could not retrieve address list from Cache")
@@ -416,50 +565,6 @@ func invalidateCache(key string) error {
 	return nil
 }
 
-func updateTypeInCache(params *RequestParams, debugInfo *Debug) error {
-	p := profiler.NewProfiler()
-	p.StartProfile("AddressHelper#updateTypeInCache")
-
-	defer func() {
-		p.EndProfileWithMetric([]string{"AddressHelper#updateTypeInCache"})
-	}()
-
-	rc := params.RequestContext
-	userID := rc.UserID
-	addressID := fmt.Sprintf("%d", params.QueryParams.AddressId)
-	addressList, _, err := getAddressListFromCache(userID, params.QueryParams, debugInfo)
-
-	if err != nil {
-		msg := "Error while fetching address list from cache"
-		logger.Error(msg)
-		return errors.New(msg)
-	}
-	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "udpateTypeInCache:id", Value: addressID})
-	if params.QueryParams.AddressType == appconstant.BILLING {
-		for _, address := range addressList {
-			if address.IsDefaultBilling == "1" {
-				address.IsDefaultBilling = "0"
-			}
-		}
-		addressList[addressID].IsDefaultBilling = "1"
-	} else if params.QueryParams.AddressType == appconstant.SHIPPING {
-		for _, address := range addressList {
-			if address.IsDefaultShipping == "1" {
-				address.IsDefaultShipping = "0"
-			}
-		}
-		addressList[addressID].IsDefaultShipping = "1"
-	}
-	err = saveDataInCache(userID, addressList)
-	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "saveDataInCache:cacheKey", Value: GetAddressListCacheKey(userID)})
-	if err != nil {
-		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "udpateTypeInCache:saveDataInCache.Err", Value: err.Error()})
-		logger.Error(fmt.Sprintf("udpateTypeInCache: Could not update address in Cache"), rc)
-		return errors.New("Could not update address type in Cache")
-	}
-	return nil
-}
-
 func GetAddressOrderCacheKey(userID string) string {
 	return fmt.Sprintf(appconstant.ORDER_CACHE_KEY, userID)
 }
@@ -493,3 +598,29 @@ func saveOrderInCache(id string, value interface{}) error {
 
 	return nil
 }
+
+//replaceAddressInCache replaces an address in the cached address list with its current state
+func replaceAddressInCache(params *RequestParams, address *AddressResponse, debugInfo *Debug) error {
+	p := profiler.NewProfiler()
+	p.StartProfile("AddressHelper#replaceAddressInCache")
+
+	defer func() {
+		p.EndProfileWithMetric([]string{"AddressHelper#replaceAddressInCache"})
+	}()
+
+	userID := params.RequestContext.UserID
+	addressList, _, err := getAddressListFromCache(userID, params, debugInfo)
+	if err != nil {
+		return err
+	}
+	if _, ok := addressList[address.Id]; !ok {
+		return errors.New("Address not found in cache")
+	}
+	addressList[address.Id] = address
+	// Sms opt-in is kept per user, not per address
+	for _, a := range addressList {
+		a.SmsOpt = address.SmsOpt
+	}
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "replaceAddressInCache:id", Value: address.Id})
+	return saveDataInCache(userID, addressList)
+}
@@ -0,0 +1,188 @@
+package address
+
+import (
+	"common/appconstant"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+//historyColumns are the customer_address columns tracked in the address history.
+//Phones and the receiver are recorded as stored, that is encrypted
+var historyColumns = []string{
+	"first_name",
+	"last_name",
+	"phone",
+	"alternate_phone",
+	"address1",
+	"address2",
+	"city",
+	"postcode",
+	"fk_customer_address_region",
+	"fk_country",
+	"address_type",
+	"label",
+	"label_type",
+	"house_number",
+	"building",
+	"street",
+	"locality",
+	"landmark",
+	"receiver_name",
+	"receiver_phone",
+	"is_default_billing",
+	"is_default_shipping",
+	"deleted_at",
+}
+
+//AddressHistory is one change of an address
+type AddressHistory struct {
+	Id            string                  `json:"id_customer_address_history"`
+	AddressId     string                  `json:"id_customer_address"`
+	Action        string                  `json:"action"`
+	Changes       map[string]*FieldChange `json:"changes"`
+	ActorType     string                  `json:"actor_type"`
+	ActorId       string                  `json:"actor_id"`
+	AppId         string                  `json:"app_id"`
+	SessionId     string                  `json:"session_id"`
+	RequestId     string                  `json:"request_id"`
+	TransactionId string                  `json:"transaction_id"`
+	CreatedAt     string                  `json:"created_at"`
+}
+
+//FieldChange holds the value of a column before and after a change. A nil value means the
+//column had no value, e.g. before a create or after a delete
+type FieldChange struct {
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+//getAddressSnapshot reads the tracked columns of an address within txObj and locks the row till
+//the transaction ends. A nil snapshot is returned if the address does not exist
+func getAddressSnapshot(txObj *sql.Tx, addressID string, userID string) (map[string]*string, error) {
+	query := `SELECT ` + strings.Join(historyColumns, ", ") + ` FROM customer_address WHERE id_customer_address = ? AND fk_customer = ? FOR UPDATE`
+	rows, err := txObj.Query(query, addressID, userID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while getting address snapshot |%s|%s|%s", appconstant.MYSQL_ERROR, err.Error(), "customer_address"))
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	values := make([]sql.NullString, len(historyColumns))
+	dest := make([]interface{}, len(historyColumns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = rows.Scan(dest...); err != nil {
+		logger.Error(fmt.Sprintf("Mysql Row Error while getting address snapshot |%s|%s|%s", appconstant.MYSQL_ERROR, err.Error(), "customer_address"))
+		return nil, err
+	}
+	snapshot := make(map[string]*string, len(historyColumns))
+	for i, column := range historyColumns {
+		if values[i].Valid {
+			v := values[i].String
+			snapshot[column] = &v
+		}
+	}
+	return snapshot, nil
+}
+
+//diffSnapshots returns the tracked columns whose value differs between before and after
+func diffSnapshots(before map[string]*string, after map[string]*string) map[string]*FieldChange {
+	changes := make(map[string]*FieldChange)
+	for _, column := range historyColumns {
+		b, a := before[column], after[column]
+		if b == nil && a == nil {
+			continue
+		}
+		if b != nil && a != nil && *b == *a {
+			continue
+		}
+		changes[column] = &FieldChange{Before: b, After: a}
+	}
+	return changes
+}
+
+//getHistoryActor returns who made the change, the support agent for admin requests else the customer
+func getHistoryActor(params *RequestParams) (actorType string, actorID string) {
+	if params.Admin != nil {
+		return appconstant.HISTORY_ACTOR_AGENT, params.Admin.AgentId
+	}
+	return appconstant.HISTORY_ACTOR_CUSTOMER, params.RequestContext.UserID
+}
+
+//recordAddressChange appends the change of an address to customer_address_history within txObj,
+//so that the history is committed or rolled back along with the change itself
+func recordAddressChange(txObj *sql.Tx, params *RequestParams, action string, addressID string, before map[string]*string, after map[string]*string, debug *Debug) error {
+	changes := diffSnapshots(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	rc := params.RequestContext
+	actorType, actorID := getHistoryActor(params)
+	query := `INSERT INTO customer_address_history SET fk_customer_address=?, fk_customer=?, action=?, changes=?, actor_type=?, actor_id=?, app_id=?, session_id=?, request_id=?, transaction_id=?, created_at=?`
+	debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "recordAddressChange:Sql", Value: query + addressID + action})
+	_, err = txObj.Exec(query, addressID, rc.UserID, action, string(changesJSON), actorType, actorID, rc.ClientAppID, rc.SessionID, rc.RequestID, rc.TransactionID, time.Now().Format(appconstant.DATETIME_FORMAT))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error while inserting address history |%s|%s|%s", appconstant.MYSQL_ERROR, err.Error(), "customer_address_history"), rc)
+		return err
+	}
+	return nil
+}
+
+//getAddressHistory gets the changes of an address of the user, latest first
+func getAddressHistory(params *RequestParams, debug *Debug) ([]*AddressHistory, error) {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return nil, err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AddressHistory#getAddressHistory")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AddressHistory#getAddressHistory"})
+	}()
+
+	rc := params.RequestContext
+	query := `SELECT id_customer_address_history, fk_customer_address, action, changes, actor_type, actor_id, IFNULL(app_id, ""), IFNULL(session_id, ""), IFNULL(request_id, ""), IFNULL(transaction_id, ""), created_at
+            FROM customer_address_history WHERE fk_customer = ? AND fk_customer_address = ?
+            ORDER BY id_customer_address_history DESC LIMIT ? OFFSET ?`
+	debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "getAddressHistory:Sql", Value: query})
+	rows, derr := db.Query(query, rc.UserID, params.QueryParams.AddressId, params.QueryParams.Limit, params.QueryParams.Offset)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while getting data from customer_address_history |%s|%s|%s", appconstant.MYSQL_ERROR, derr.Error(), "customer_address_history"), rc)
+		return nil, derr
+	}
+	defer rows.Close()
+
+	history := make([]*AddressHistory, 0)
+	for rows.Next() {
+		var (
+			h         AddressHistory
+			changes   []byte
+			createdAt []byte
+		)
+		if serr := rows.Scan(&h.Id, &h.AddressId, &h.Action, &changes, &h.ActorType, &h.ActorId, &h.AppId, &h.SessionId, &h.RequestId, &h.TransactionId, &createdAt); serr != nil {
+			logger.Warning(fmt.Sprintf("Mysql Row Error while getting row from customer_address_history table %v", serr), rc)
+			continue
+		}
+		if jerr := json.Unmarshal(changes, &h.Changes); jerr != nil {
+			logger.Warning(fmt.Sprintf("Invalid changes in customer_address_history %s: %v", h.Id, jerr), rc)
+		}
+		createdAtTime, _ := time.Parse(time.RFC3339, string(createdAt))
+		h.CreatedAt = createdAtTime.Format(appconstant.DATETIME_FORMAT)
+		history = append(history, &h)
+	}
+	return history, nil
+}
@@ -0,0 +1,63 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AddressHistoryExecutor retrieves the change history of an address of the user
+type AddressHistoryExecutor struct {
+	id string
+}
+
+func (n *AddressHistoryExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n AddressHistoryExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (a AddressHistoryExecutor) Name() string {
+	return "AddressHistoryExecutor"
+}
+
+func (a AddressHistoryExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AddressHistoryExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AddressHistoryExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Address History Executor", "Address History Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("AddressHistoryExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	if params.QueryParams.AddressId == 0 {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "Id is missing or not a number"}
+	}
+
+	debugInfo := new(Debug)
+	historyResult, err := GetAddressHistory(params.withContext(io.Context()), debugInfo)
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("There is some error occured while getting the address history %v", err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, historyResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting address history result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
@@ -0,0 +1,63 @@
+package address
+
+import (
+	"common/appconstant"
+
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Address history", func() {
+	stored := map[string]*string{
+		"first_name": stringPtr("Asha"),
+		"city":       stringPtr("Pune"),
+		"phone":      stringPtr("enc-9876"),
+		"label":      nil,
+	}
+
+	gk.It("should record only the columns a change modified", func() {
+		tests := []struct {
+			name    string
+			before  map[string]*string
+			after   map[string]*string
+			changes map[string]*FieldChange
+		}{
+			{"nothing changed", stored, map[string]*string{
+				"first_name": stringPtr("Asha"), "city": stringPtr("Pune"), "phone": stringPtr("enc-9876"),
+			}, map[string]*FieldChange{}},
+			{"value changed", stored, map[string]*string{
+				"first_name": stringPtr("Asha"), "city": stringPtr("Mumbai"), "phone": stringPtr("enc-9876"),
+			}, map[string]*FieldChange{"city": {Before: stringPtr("Pune"), After: stringPtr("Mumbai")}}},
+			{"value set", stored, map[string]*string{
+				"first_name": stringPtr("Asha"), "city": stringPtr("Pune"), "phone": stringPtr("enc-9876"), "label": stringPtr("Home"),
+			}, map[string]*FieldChange{"label": {Before: nil, After: stringPtr("Home")}}},
+			{"value cleared", stored, map[string]*string{
+				"first_name": stringPtr("Asha"), "city": stringPtr("Pune"),
+			}, map[string]*FieldChange{"phone": {Before: stringPtr("enc-9876"), After: nil}}},
+			{"create", nil, stored, map[string]*FieldChange{
+				"first_name": {Before: nil, After: stringPtr("Asha")},
+				"city":       {Before: nil, After: stringPtr("Pune")},
+				"phone":      {Before: nil, After: stringPtr("enc-9876")},
+			}},
+			// Only the tracked columns are recorded
+			{"untracked column", map[string]*string{"version": stringPtr("1")}, map[string]*string{"version": stringPtr("2")},
+				map[string]*FieldChange{}},
+		}
+		for _, test := range tests {
+			gm.Expect(diffSnapshots(test.before, test.after)).To(gm.Equal(test.changes), test.name)
+		}
+	})
+
+	gk.It("should record the support agent as the actor of an admin change", func() {
+		customer := &RequestParams{RequestContext: utilHttp.RequestContext{UserID: "1773895"}}
+		actorType, actorID := getHistoryActor(customer)
+		gm.Expect(actorType).To(gm.Equal(appconstant.HISTORY_ACTOR_CUSTOMER))
+		gm.Expect(actorID).To(gm.Equal("1773895"))
+
+		admin := &RequestParams{RequestContext: customer.RequestContext, Admin: &AdminContext{AgentId: "cs-agent"}}
+		actorType, actorID = getHistoryActor(admin)
+		gm.Expect(actorType).To(gm.Equal(appconstant.HISTORY_ACTOR_AGENT))
+		gm.Expect(actorID).To(gm.Equal("cs-agent"))
+	})
+})
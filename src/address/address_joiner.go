@@ -0,0 +1,61 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AddressJoiner joins the forked paths of a list or detail request. The path that read the addresses
+//goes on, with the names of the regions the other path read set on the addresses, so that a cached list
+//shows the current region names. The join fails if no path read the addresses
+type AddressJoiner struct {
+	id string
+}
+
+func (n *AddressJoiner) SetID(id string) {
+	n.id = id
+}
+
+func (n AddressJoiner) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n AddressJoiner) Name() string {
+	return "AddressJoiner"
+}
+
+func (n AddressJoiner) Join(paths []*workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	var addressPath *workflow.WorkFlowData
+	var regions map[string]*RegionMetadata
+	for _, path := range paths {
+		if r, _ := path.IOData.Get(appconstant.IO_ADDRESS_RESULT); r != nil {
+			addressPath = path
+		}
+		if m, _ := path.IOData.Get(appconstant.IO_REGION_METADATA); m != nil {
+			regions, _ = m.(map[string]*RegionMetadata)
+		}
+	}
+	if addressPath == nil {
+		return *paths[0], getForkError(paths)
+	}
+	if r, _ := addressPath.IOData.Get(appconstant.IO_ADDRESS_RESULT); regions != nil {
+		if result, ok := r.(*AddressResult); ok {
+			setRegionNames(result.AddressList, regions)
+		}
+	}
+	return *addressPath, nil
+}
+
+//getForkError gets the error of the path that failed to read the addresses
+func getForkError(paths []*workflow.WorkFlowData) error {
+	for _, path := range paths {
+		for _, state := range path.GetWorkflowState() {
+			if err, ok := state.(error); ok {
+				return err
+			}
+		}
+	}
+	return &constants.AppError{Code: constants.ResourceErrorCode, Message: "The addresses were not read"}
+}
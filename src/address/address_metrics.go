@@ -0,0 +1,64 @@
+package address
+
+import (
+	"common/appconfig"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/monitor"
+)
+
+//getMetricTags tags the metrics of a request with its API version, the app it came from and the experiment
+//variants it is in. Only the configured apps and experiments are tagged by name, the buckets sent by the
+//clients are not, so that the number of series stays bounded
+func getMetricTags(params *RequestParams) []string {
+	if params == nil {
+		return []string{"api_version:unknown", "app_id:unknown", "experiments:none"}
+	}
+	variants := make([]string, 0, len(params.Experiments))
+	for experiment, variant := range params.Experiments {
+		variants = append(variants, experiment+"="+variant)
+	}
+	sort.Strings(variants)
+	return []string{
+		"api_version:" + getTagValue(params.APIVersion, "unknown"),
+		"app_id:" + getMetricAppID(params.RequestContext.ClientAppID),
+		"experiments:" + getTagValue(strings.Join(variants, ","), "none"),
+	}
+}
+
+//getMetricAppID gets the tag of the app a request came from, other for an app that is not configured
+func getMetricAppID(appID string) string {
+	if appID == "" {
+		return "unknown"
+	}
+	if appConfig, err := appconfig.GetAddressServiceConfig(); err == nil && appConfig.Metrics != nil {
+		for _, id := range appConfig.Metrics.AppIds {
+			if id == appID {
+				return appID
+			}
+		}
+	}
+	return "other"
+}
+
+func getTagValue(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+//countMetric counts an address metric with the tags of the request and the given ones. Nothing is counted
+//when no monitor is configured, a request never fails on its metrics
+func countMetric(name string, value int64, params *RequestParams, tags ...string) {
+	m := monitor.GetInstance()
+	if m == nil {
+		return
+	}
+	if err := m.Count(name, value, append(getMetricTags(params), tags...), 1); err != nil {
+		logger.Error(fmt.Sprintf("Monitoring Error %v", err))
+	}
+}
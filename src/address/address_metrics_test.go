@@ -0,0 +1,59 @@
+package address
+
+import (
+	"common/appconfig"
+
+	"github.com/jabong/florest-core/src/common/config"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Address metrics", func() {
+	var previous interface{}
+
+	gk.BeforeEach(func() {
+		previous = config.GlobalAppConfig.ApplicationConfig
+		config.GlobalAppConfig.ApplicationConfig = &appconfig.AddressServiceConfig{
+			Metrics: &appconfig.MetricsConfig{AppIds: []string{"android", "ios"}},
+		}
+	})
+
+	gk.AfterEach(func() {
+		config.GlobalAppConfig.ApplicationConfig = previous
+	})
+
+	gk.It("should tag the metrics with the API version, app and experiment variants of the request", func() {
+		tests := []struct {
+			name   string
+			params *RequestParams
+			tags   []string
+		}{
+			{"no request", nil, []string{"api_version:unknown", "app_id:unknown", "experiments:none"}},
+			{"no experiments", &RequestParams{}, []string{"api_version:unknown", "app_id:unknown", "experiments:none"}},
+			{"every tag", &RequestParams{
+				APIVersion:     "V1",
+				RequestContext: utilHttp.RequestContext{ClientAppID: "android"},
+				Experiments:    map[string]string{"listflow": "parallel"},
+			}, []string{"api_version:V1", "app_id:android", "experiments:listflow=parallel"}},
+			// The variants are sorted so that a request is always tagged the same
+			{"variants sorted", &RequestParams{
+				Experiments: map[string]string{"listflow": "control", "dedupe": "loose", "checkout": "new"},
+			}, []string{"api_version:unknown", "app_id:unknown", "experiments:checkout=new,dedupe=loose,listflow=control"}},
+			// Any app id and bucket can be sent, only the configured ones are tagged by name
+			{"unknown app", &RequestParams{RequestContext: utilHttp.RequestContext{ClientAppID: "curl-1234"}},
+				[]string{"api_version:unknown", "app_id:other", "experiments:none"}},
+			{"buckets not in an experiment", &RequestParams{Buckets: map[string]string{"listflow": "serial-1234"}},
+				[]string{"api_version:unknown", "app_id:unknown", "experiments:none"}},
+		}
+		for _, test := range tests {
+			gm.Expect(getMetricTags(test.params)).To(gm.Equal(test.tags), test.name)
+		}
+	})
+
+	gk.It("should tag every app as other when no app is configured", func() {
+		config.GlobalAppConfig.ApplicationConfig = &appconfig.AddressServiceConfig{}
+		params := &RequestParams{RequestContext: utilHttp.RequestContext{ClientAppID: "android"}}
+		gm.Expect(getMetricTags(params)).To(gm.ContainElement("app_id:other"))
+	})
+})
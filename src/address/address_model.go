@@ -2,6 +2,7 @@ package address
 
 import (
 	"common/appconstant"
+	"database/sql"
 	"errors"
 	"fmt"
 	"regexp"
@@ -47,7 +48,7 @@ func getRegionId(regionId string, debug *Debug) (id string, countryId string, er
 }
 
 func getAddressList(params *RequestParams, addressId string, debug *Debug) (address map[string]*AddressResponse, order []string, err error) {
-	db, err := sqldb.Get("mysdb")
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
 	prof := profiler.NewProfiler()
 	prof.StartProfile("address-address_model-getAddressList")
 	defer func() {
@@ -60,11 +61,11 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 		return nil, nil, errors.New("CustomerID not present")
 	}
 
-	sql := `SELECT DISTINCT(ca.id_customer_address) as id,ca.first_name, ca.last_name, ca.phone, IFNULL(ca.alternate_phone, ""), ca.address1, ca.address2, ca.city, ca.is_default_billing, ca.is_default_shipping, ca.fk_customer, ca.created_at, ca.updated_at, r.name AS region, r.id_customer_address_region, postcode, country.id_country as country, adi.sms_opt, IFNULL(ca.address_type, 0)
+	sql := `SELECT DISTINCT(ca.id_customer_address) as id,ca.first_name, ca.last_name, ca.phone, IFNULL(ca.alternate_phone, ""), ca.address1, ca.address2, ca.city, ca.is_default_billing, ca.is_default_shipping, ca.fk_customer, ca.created_at, ca.updated_at, IFNULL(ca.fk_customer_address_region, ""), postcode, country.id_country as country, adi.sms_opt, IFNULL(ca.address_type, 0), ca.version, IFNULL(ca.label, ""), ca.label_type, IFNULL(ca.house_number, ""), IFNULL(ca.building, ""), IFNULL(ca.street, ""), IFNULL(ca.locality, ""), IFNULL(ca.landmark, ""), IFNULL(cap.instructions, ""), IFNULL(cap.delivery_windows, ""), IFNULL(cap.weekend_delivery, 1), IFNULL(ca.receiver_name, ""), IFNULL(ca.receiver_phone, ""), ca.phone_verified
             FROM customer_address ca JOIN country ON fk_country = id_country
-            LEFT JOIN customer_address_region r ON fk_customer_address_region=r.id_customer_address_region
             LEFT JOIN customer_additional_info adi ON adi.fk_customer=ca.fk_customer
-            WHERE ca.fk_customer=` + customerId
+            LEFT JOIN customer_address_preference cap ON cap.fk_customer_address=ca.id_customer_address
+            WHERE ca.deleted_at IS NULL AND ca.fk_customer=` + customerId
 
 	if addressId != "" {
 		sql = sql + ` AND id_customer_address = ` + addressId
@@ -82,14 +83,18 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 	encryptedFields := make([]EncryptedFields, 0)
 	for rows.Next() {
 		var (
-			fname, lname, address1, address2, city, region, phone, altPhone, smsOpt                     []byte
+			fname, lname, address1, address2, city, phone, altPhone, smsOpt                             []byte
 			id, isBilling, isShipping, fkCustomer, customerAddressRegionId, country, postcode, isOffice []byte
+			version, label, labelType                                                                   []byte
+			house, building, street, locality, landmark                                                 []byte
+			instructions, deliveryWindows, weekendDelivery, receiverName, receiverPhone                 []byte
+			phoneVerified                                                                               []byte
 			createdAt                                                                                   []byte
 			updatedAt                                                                                   time.Time
 		)
 		encFields := EncryptedFields{}
 
-		err = rows.Scan(&id, &fname, &lname, &phone, &altPhone, &address1, &address2, &city, &isBilling, &isShipping, &fkCustomer, &createdAt, &updatedAt, &region, &customerAddressRegionId, &postcode, &country, &smsOpt, &isOffice)
+		err = rows.Scan(&id, &fname, &lname, &phone, &altPhone, &address1, &address2, &city, &isBilling, &isShipping, &fkCustomer, &createdAt, &updatedAt, &customerAddressRegionId, &postcode, &country, &smsOpt, &isOffice, &version, &label, &labelType, &house, &building, &street, &locality, &landmark, &instructions, &deliveryWindows, &weekendDelivery, &receiverName, &receiverPhone, &phoneVerified)
 		if err != nil {
 			logger.Warning(fmt.Sprintf("Mysql Row Error while getting row from customer_address table", err))
 			continue
@@ -104,7 +109,6 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 		resp.Address1 = sanitize(string(address1), false)
 		resp.Address2 = sanitize(string(address2), false)
 		resp.City = sanitize(string(city), false)
-		resp.RegionName = string(region)
 		resp.AddressRegion = string(customerAddressRegionId)
 		resp.PostCode = string(postcode)
 		resp.Country = string(country)
@@ -121,10 +125,22 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 		}
 		resp.UpdatedAt = updatedAt.Format(appconstant.DATETIME_FORMAT)
 		resp.SmsOpt = string(smsOpt)
+		resp.PhoneVerified = string(phoneVerified)
+		resp.Label = string(label)
+		resp.LabelType = string(labelType)
+		resp.House = sanitize(string(house), false)
+		resp.Building = sanitize(string(building), false)
+		resp.Street = sanitize(string(street), false)
+		resp.Locality = sanitize(string(locality), false)
+		resp.Landmark = sanitize(string(landmark), false)
+		resp.Preferences = newDeliveryPreferences(string(instructions), string(deliveryWindows), string(weekendDelivery))
+		setAddressVersion(resp, string(version))
 
 		encFields.Id = string(id)
 		encFields.EncryptedPhone = string(phone)
 		encFields.EncryptedAlternatePhone = string(altPhone)
+		encFields.EncryptedReceiverName = string(receiverName)
+		encFields.EncryptedReceiverPhone = string(receiverPhone)
 		encryptedFields = append(encryptedFields, encFields)
 		addresses[index] = resp
 		order = append(order, index)
@@ -137,6 +153,9 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 		}
 		mergeDecryptedFieldsWithAddressResult(res, &addresses)
 	}
+	// The regions are not read here, the list and detail workflows read them while the addresses are read
+	// and the AddressJoiner sets their names. Names already in memory are set for the other callers
+	setRegionNames(addresses, getLoadedRegionMetadata())
 
 	if addressId == "" {
 		if len(addresses) != 0 {
@@ -153,8 +172,8 @@ func getAddressList(params *RequestParams, addressId string, debug *Debug) (addr
 	return addresses, order, nil
 }
 
-func addAddress(userID string, a AddressRequest, debug *Debug) (int64, error) {
-	db, _ := sqldb.Get("mysdb")
+func addAddress(params *RequestParams, debug *Debug) (int64, error) {
+	db, _ := sqldb.GetWithContext(params.Context(), "mysdb")
 	prof := profiler.NewProfiler()
 	prof.StartProfile("AddressModel#addAddress")
 
@@ -162,7 +181,9 @@ func addAddress(userID string, a AddressRequest, debug *Debug) (int64, error) {
 		prof.EndProfileWithMetric([]string{"AddressModel#addAddress"})
 	}()
 
-	sql := `INSERT INTO customer_address SET first_name=?, address1=?, phone=?, postcode=?, city=?, fk_customer_address_region=?, fk_country=?, fk_customer=?, created_at=?, validation_flag=?, last_name=?`
+	userID := params.RequestContext.UserID
+	a := params.QueryParams.Address
+	sql := `INSERT INTO customer_address SET first_name=?, address1=?, phone=?, postcode=?, city=?, fk_customer_address_region=?, fk_country=?, fk_customer=?, created_at=?, validation_flag=?, last_name=?, label=?, label_type=?, house_number=?, building=?, street=?, locality=?, landmark=?, receiver_name=?, receiver_phone=?`
 	if a.Address2 != "" {
 		sql = sql + `, address2='` + a.Address2 + `'`
 	}
@@ -173,7 +194,7 @@ func addAddress(userID string, a AddressRequest, debug *Debug) (int64, error) {
 		sql = sql + `, address_type='` + a.IsOffice + `'`
 	}
 	// Check if the user has any other addresses, if not, mark this as default
-	flag, err := isFirstAddress(userID, debug)
+	flag, err := isFirstAddress(userID, params, debug)
 	if flag == true {
 		sql = sql + `, is_default_shipping = 1, is_default_billing = 1`
 	} else if err != nil {
@@ -193,22 +214,32 @@ func addAddress(userID string, a AddressRequest, debug *Debug) (int64, error) {
 		logger.Error(fmt.Sprintf("|%s|%s|%s", appconstant.MYSQL_ERROR, terr.Error(), "customer_address"))
 		return 0, terr
 	}
-	validationFlag := validateAddress(a.Address1 + a.Address2)
-	rows, err1 := txObj.Exec(sql, a.FirstName, a.Address1, a.EncryptedPhone, a.PostCode, a.City, customerAddressRegion, countryID, userID, time.Now().Format(appconstant.DATETIME_FORMAT), validationFlag, a.LastName)
+	validationFlag := getValidationFlag(params, a.Address1+a.Address2)
+	rows, err1 := txObj.Exec(sql, a.FirstName, a.Address1, a.EncryptedPhone, a.PostCode, a.City, customerAddressRegion, countryID, userID, time.Now().Format(appconstant.DATETIME_FORMAT), validationFlag, a.LastName, getLabelValue(a.Label), a.LabelType, getComponentValue(a.House), getComponentValue(a.Building), getComponentValue(a.Street), getComponentValue(a.Locality), getComponentValue(a.Landmark), getEncryptedValue(a.ReceiverName, a.EncryptedReceiverName), getEncryptedValue(a.ReceiverPhone, a.EncryptedReceiverPhone))
 	if err1 != nil {
 		txObj.Rollback()
 		logger.Error(fmt.Sprintf("|%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"))
 		return 0, err1
 	}
-	err1 = txObj.Commit()
+	id, err1 := rows.LastInsertId()
 	if err1 != nil {
 		txObj.Rollback()
-		logger.Error(fmt.Sprintf("AddAddress::CommitError::|%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"))
+		logger.Error(fmt.Sprintf("Mysql Error while retrieving last inserted row into customer_address table |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"))
 		return 0, err1
 	}
-	id, err1 := rows.LastInsertId()
+	addressID := strconv.FormatInt(id, 10)
+	after, err1 := getAddressSnapshot(txObj, addressID, userID)
+	if err1 == nil {
+		err1 = recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_CREATE, addressID, nil, after, debug)
+	}
 	if err1 != nil {
-		logger.Error(fmt.Sprintf("Mysql Error while retrieving last inserted row into customer_address table |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"))
+		txObj.Rollback()
+		return 0, err1
+	}
+	err1 = txObj.Commit()
+	if err1 != nil {
+		txObj.Rollback()
+		logger.Error(fmt.Sprintf("AddAddress::CommitError::|%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"))
 		return 0, err1
 	}
 	logger.Info(fmt.Sprintf("Last Insert Id %s", id))
@@ -217,7 +248,7 @@ func addAddress(userID string, a AddressRequest, debug *Debug) (int64, error) {
 }
 
 func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
-	db, err := sqldb.Get("mysdb")
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
 	prof := profiler.NewProfiler()
 	prof.StartProfile("address-address_model-updateAddress")
 	defer func() {
@@ -228,7 +259,8 @@ func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
 	userId := rc.UserID
 	a := params.QueryParams.Address
 	var query string
-	sql := `UPDATE customer_address SET first_name = '%s', address1 = '%s', phone = '%s', city = '%s', postcode = '%s', fk_customer_address_region = '%s', fk_country = '%s' , address_type = '%s', validation_flag = '%s'`
+	// The verification of the phone is reset if the phone changes, it has to come before the phone is set
+	sql := `UPDATE customer_address SET phone_verified = IF(phone <=> ?, phone_verified, 0), first_name = '%s', address1 = '%s', phone = '%s', city = '%s', postcode = '%s', fk_customer_address_region = '%s', fk_country = '%s' , address_type = '%s', validation_flag = '%s', version = version + 1`
 	if a.LastName != "" {
 		sql = sql + `, last_name = '` + a.LastName + `'`
 	}
@@ -239,13 +271,34 @@ func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
 		sql = sql + `, alternate_phone = '` + a.EncryptedAlternatePhone + `'`
 	}
 
-	sql = sql + ` WHERE fk_customer = ? and id_customer_address= ?` // + fmt.Sprintf("%d", uint32(a.Id))
+	// The label is user text, it is passed as an argument rather than formatted into the query
+	args := []interface{}{a.EncryptedPhone}
+	if a.Label != "" {
+		sql = sql + `, label = ?`
+		args = append(args, a.Label)
+	}
+	if a.LabelType != "" {
+		sql = sql + `, label_type = ?`
+		args = append(args, a.LabelType)
+	}
+	// PUT replaces the address, components not sent are cleared so they never contradict the address lines
+	for _, field := range addressComponentFields {
+		sql = sql + `, ` + addressComponentColumns[field] + ` = ?`
+		args = append(args, getComponentValue(getAddressComponent(a, field)))
+	}
+	// Likewise a receiver not sent is removed
+	sql = sql + `, receiver_name = ?, receiver_phone = ?`
+	args = append(args, getEncryptedValue(a.ReceiverName, a.EncryptedReceiverName), getEncryptedValue(a.ReceiverPhone, a.EncryptedReceiverPhone))
+
+	sql = sql + ` WHERE fk_customer = ? and id_customer_address= ? AND deleted_at IS NULL` // + fmt.Sprintf("%d", uint32(a.Id))
+	condition, conditionArgs := versionCondition(params)
+	sql = sql + condition
 
 	customerAddressRegion, countryId, err := getRegionId(a.AddressRegion, debugInfo)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Error while getting Region Info of the user"), rc)
 	}
-	validationFlag := validateAddress(a.Address1 + a.Address2)
+	validationFlag := getValidationFlag(params, a.Address1+a.Address2)
 	query = fmt.Sprintf(sql, a.FirstName, a.Address1, a.EncryptedPhone, a.City, a.PostCode, customerAddressRegion, countryId, a.IsOffice, validationFlag)
 	addressId := strconv.Itoa(params.QueryParams.AddressId)
 	logger.Info(fmt.Sprintf("Update Address query: %s", query), rc)
@@ -254,7 +307,21 @@ func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
 	var err1, err2 error
 	txObj, terr := db.GetTxnObj()
 	if terr == nil {
-		_, err1 = txObj.Exec(query, userId, addressId)
+		var before, after map[string]*string
+		before, err1 = getAddressSnapshot(txObj, addressId, userId)
+		if err1 == nil {
+			res, xerr := txObj.Exec(query, append(append(args, userId, addressId), conditionArgs...)...)
+			err1 = xerr
+			if err1 == nil {
+				err1 = checkVersionUpdated(params, res)
+			}
+		}
+		if err1 == nil {
+			after, err1 = getAddressSnapshot(txObj, addressId, userId)
+		}
+		if err1 == nil {
+			err1 = recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_UPDATE, addressId, before, after, debugInfo)
+		}
 		if err1 != nil {
 			logger.Error(fmt.Sprintf("Error while updating user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
 		}
@@ -271,6 +338,10 @@ func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
 			invalidateCache(key)
 			key = GetAddressOrderCacheKey(userId)
 			invalidateCache(key)
+			if err1 != nil {
+				return err1
+			}
+			return err2
 		}
 		err = txObj.Commit()
 		if err != nil {
@@ -280,12 +351,13 @@ func updateAddressInDb(params *RequestParams, debugInfo *Debug) (err error) {
 		}
 	} else {
 		logger.Error(fmt.Sprintf("Transaction Error:: Error while updating user address |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
 	}
 	return nil
 }
 
 func deleteAddress(params *RequestParams, cacheErr error, debugInfo *Debug, e chan error) (err error) {
-	db, err := sqldb.Get("mysdb")
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
 	prof := profiler.NewProfiler()
 	prof.StartProfile("address-address_model-deleteAddress")
 	defer func() {
@@ -295,22 +367,46 @@ func deleteAddress(params *RequestParams, cacheErr error, debugInfo *Debug, e ch
 	userId := rc.UserID
 	addressId := strconv.Itoa(params.QueryParams.AddressId)
 
-	sql := `DELETE FROM customer_address WHERE id_customer_address=? AND fk_customer=?`
+	// Addresses are only marked as deleted so that they can be restored within the restore window,
+	// purgeDeletedAddresses removes them once the window has passed
+	sql := `UPDATE customer_address SET deleted_at=?, version = version + 1 WHERE id_customer_address=? AND fk_customer=? AND deleted_at IS NULL`
+	condition, conditionArgs := versionCondition(params)
+	sql = sql + condition
 
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "deleteAddress:Sql", Value: sql + "id_customer_address: " + addressId + "fk_customer: " + userId})
 
 	txObj, _ := db.GetTxnObj()
-	deleteResult, err1 := txObj.Exec(sql, addressId, userId)
+	before, err1 := getAddressSnapshot(txObj, addressId, userId)
+	if err1 != nil {
+		txObj.Rollback()
+		e <- err1
+		return
+	}
+	args := append([]interface{}{time.Now().Format(appconstant.DATETIME_FORMAT), addressId, userId}, conditionArgs...)
+	deleteResult, err1 := txObj.Exec(sql, args...)
 	if err1 != nil {
 		txObj.Rollback()
 		logger.Error(fmt.Sprintf("Error while delete user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
 		e <- err1
+		return
 	}
 	rowsaffected, _ := deleteResult.RowsAffected()
 	if rowsaffected == 0 {
 		txObj.Rollback()
-		addressNotFoundError := errors.New("Address not found")
-		e <- addressNotFoundError
+		//The address was found when the If-Match was checked
+		if err1 = checkVersionUpdated(params, deleteResult); err1 == nil {
+			err1 = errors.New("Address not found")
+		}
+		e <- err1
+		return
+	}
+	after, err1 := getAddressSnapshot(txObj, addressId, userId)
+	if err1 == nil {
+		err1 = recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_DELETE, addressId, before, after, debugInfo)
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		e <- err1
 		return
 	}
 	err = txObj.Commit()
@@ -330,14 +426,22 @@ func deleteAddress(params *RequestParams, cacheErr error, debugInfo *Debug, e ch
 	return
 }
 
-func getAddressTypeSql(ty string) string {
-	var updateTypeField string
+//getLabelValue returns the label to store, NULL if the address has no label
+func getLabelValue(label string) interface{} {
+	if label == "" {
+		return nil
+	}
+	return label
+}
+
+//getDefaultColumn returns the column flagging the default address of a type, empty for other types
+func getDefaultColumn(ty string) string {
 	if ty == appconstant.BILLING {
-		updateTypeField = ` is_default_billing = 1`
+		return "is_default_billing"
 	} else if ty == appconstant.SHIPPING {
-		updateTypeField = ` is_default_shipping = 1`
+		return "is_default_shipping"
 	}
-	return updateTypeField
+	return ""
 }
 
 func getUpdateSmsOptOfUserQuery() string {
@@ -345,6 +449,13 @@ func getUpdateSmsOptOfUserQuery() string {
 	return sql
 }
 
+//getValidationFlag gets the validation_flag of the address lines written by a request and counts it
+func getValidationFlag(params *RequestParams, address string) string {
+	flag := validateAddress(address)
+	countMetric(appconstant.VALIDATION_FLAG_METRIC, 1, params, "flag:"+flag)
+	return flag
+}
+
 func validateAddress(address string) string {
 
 	//To check the same character is not repeated 4 times
@@ -396,110 +507,127 @@ func validateAddress(address string) string {
 	return flag
 }
 
-func updateType(params *RequestParams, debugInfo *Debug, e chan error) {
-	db, _ := sqldb.Get("mysdb")
+//updateType makes an address the default billing or shipping address of its customer. The addresses of the
+//customer are locked and the previous defaults reset before the new default is set, all in one transaction
+func updateType(params *RequestParams, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
 	prof := profiler.NewProfiler()
 	prof.StartProfile("address-address_model-updateType")
 	defer func() {
 		prof.EndProfileWithMetric([]string{"address-address_model-updateType"})
 	}()
 	rc := params.RequestContext
-	userId := rc.UserID
-	updateTypeField := getAddressTypeSql(params.QueryParams.AddressType)
-	query := `UPDATE customer_address SET ` + updateTypeField + ` WHERE fk_customer = ? and id_customer_address= ?`
-	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "updateType:Sql", Value: query + "fk_customer: " + userId + "id_customer_address: " + string(params.QueryParams.AddressId)})
-	txObj, _ := db.GetTxnObj()
-	updateTypeResult, err1 := txObj.Exec(query, userId, params.QueryParams.AddressId)
-	if err1 != nil {
-		txObj.Rollback()
-		key := GetAddressListCacheKey(userId)
-		invalidateCache(key)
-		key = GetAddressOrderCacheKey(userId)
-		invalidateCache(key)
-		logger.Error(fmt.Sprintf("Error while updating  address type|%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
-		e <- err1
+	addressId := strconv.Itoa(params.QueryParams.AddressId)
+	defaultColumn := getDefaultColumn(params.QueryParams.AddressType)
+	if defaultColumn == "" {
+		return fmt.Errorf("Invalid address type %s", params.QueryParams.AddressType)
 	}
-	rowsaffected, _ := updateTypeResult.RowsAffected()
-	if rowsaffected == 0 {
+
+	txObj, terr := db.GetTxnObj()
+	if terr != nil {
+		logger.Error(fmt.Sprintf("Transaction Error:: Error while updating address type |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
+	}
+	err1 := setAddressDefault(txObj, params, defaultColumn, addressId, debugInfo)
+	if err1 != nil {
 		txObj.Rollback()
-		addressNotFoundError := errors.New("Address not found")
-		e <- addressNotFoundError
-		return
+		if err1 != errAddressNotFound && err1 != errAddressChanged {
+			logger.Error(fmt.Sprintf("Error while updating address type|%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
+		}
+		return err1
 	}
 	err1 = txObj.Commit()
 	if err1 != nil {
 		txObj.Rollback()
 		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "UpdateType::CommitTransactionError:", Value: err1.Error()})
-		e <- err1
-		return
+		return err1
 	}
+	return nil
+}
 
-	// Reset defaults for other addresses
-	addressTypeSQL := ""
-	if params.QueryParams.AddressType == appconstant.BILLING {
-		addressTypeSQL = ` is_default_billing = 1`
-	} else if params.QueryParams.AddressType == appconstant.SHIPPING {
-		addressTypeSQL = ` is_default_shipping = 1`
-	}
-	query = `SELECT id_customer_address FROM customer_address WHERE ` + addressTypeSQL + ` AND fk_customer = ?`
-	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "resetDefaultAddress#Sql", Value: query + userId})
-	rows, err := db.Query(query, userId)
+//setAddressDefault makes an address the default of defaultColumn in the transaction of updateType
+func setAddressDefault(txObj *sql.Tx, params *RequestParams, defaultColumn string, addressId string, debugInfo *Debug) error {
+	userId := params.RequestContext.UserID
+	// Locking every address of the customer serialises concurrent changes of the default
+	lockQuery := `SELECT id_customer_address, ` + defaultColumn + ` FROM customer_address WHERE fk_customer = ? AND deleted_at IS NULL ORDER BY id_customer_address FOR UPDATE`
+	resetQuery := `UPDATE customer_address SET ` + defaultColumn + ` = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ?`
+	setQuery := `UPDATE customer_address SET ` + defaultColumn + ` = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ?`
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "updateType:Sql", Value: setQuery + "fk_customer: " + userId + "id_customer_address: " + addressId})
+
+	previous, found, err := getDefaultAddressIds(txObj, lockQuery, userId, addressId)
 	if err != nil {
-		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "resetDefaultAddress#Err", Value: err.Error()})
-		logger.Error(fmt.Sprintf("Mysql Error while getting data from customer_address |%s|%s", appconstant.MYSQL_ERROR, err.Error()))
-		e <- err
-		return
+		return err
 	}
-	var addressID string
-	resetAddressTypeSql := ""
-	if params.QueryParams.AddressType == appconstant.BILLING {
-		resetAddressTypeSql = `is_default_billing = 0`
-	} else if params.QueryParams.AddressType == appconstant.SHIPPING {
-		resetAddressTypeSql = `is_default_shipping = 0`
+	if !found {
+		return errAddressNotFound
 	}
-	resetQuery := `UPDATE customer_address SET ` + resetAddressTypeSql + ` WHERE id_customer_address = ?`
-	for rows.Next() {
-		err1 := rows.Scan(&addressID)
-		if err1 != nil {
-			debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "resetDefaultAddress#Err", Value: err1.Error()})
-			logger.Error(fmt.Sprintf("Mysql Error while getting data from customer_address |%s|%s", appconstant.MYSQL_ERROR, err1.Error()))
-			e <- err1
-			return
+	// The previous defaults are reset first, the database allows only one default of each type per customer
+	for _, id := range previous {
+		if err = changeAddressDefault(txObj, params, resetQuery, id, debugInfo); err != nil {
+			return err
 		}
-		txObj, terr := db.GetTxnObj()
-		if terr == nil {
-			if addressID != strconv.Itoa(params.QueryParams.AddressId) {
-				_, err1 = txObj.Exec(resetQuery, addressID)
-				if err1 != nil {
-					txObj.Rollback()
-					key := GetAddressListCacheKey(userId)
-					invalidateCache(key)
-					key = GetAddressOrderCacheKey(userId)
-					invalidateCache(key)
-					logger.Error(fmt.Sprintf("Error while updating user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
-				}
-			}
-		} else {
-			logger.Error(fmt.Sprintf("Transaction Error:: Error while updating user address |%s|%s", appconstant.MYSQL_ERROR, terr), rc)
+	}
+	// The If-Match of the request names the address set as the default
+	condition, conditionArgs := versionCondition(params)
+	return changeAddressDefault(txObj, params, setQuery+condition, addressId, debugInfo, conditionArgs...)
+}
+
+//getDefaultAddressIds locks the addresses of the customer and returns the other addresses that are default
+//with lockQuery, and whether the address being made default exists
+func getDefaultAddressIds(txObj *sql.Tx, lockQuery string, userId string, addressId string) ([]string, bool, error) {
+	rows, err := txObj.Query(lockQuery, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	previous := []string{}
+	found := false
+	for rows.Next() {
+		var id, isDefault string
+		if err = rows.Scan(&id, &isDefault); err != nil {
+			return nil, false, err
 		}
-		err1 = txObj.Commit()
-		if err != nil {
-			txObj.Rollback()
-			debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "AddAddress::CommitTransactionError:", Value: err.Error()})
-			e <- err
+		if id == addressId {
+			found = true
+		} else if isDefault == "1" {
+			previous = append(previous, id)
 		}
 	}
+	return previous, found, rows.Err()
+}
 
-	e <- nil
-	return
+//changeAddressDefault runs a change of the default flag of an address and records it in the history
+func changeAddressDefault(txObj *sql.Tx, params *RequestParams, query string, addressId string, debugInfo *Debug, conditionArgs ...interface{}) error {
+	userId := params.RequestContext.UserID
+	before, err := getAddressSnapshot(txObj, addressId, userId)
+	if err != nil {
+		return err
+	}
+	res, err := txObj.Exec(query, append([]interface{}{addressId, userId}, conditionArgs...)...)
+	if err != nil {
+		return err
+	}
+	if len(conditionArgs) != 0 {
+		if err = checkVersionUpdated(params, res); err != nil {
+			return err
+		}
+	}
+	after, err := getAddressSnapshot(txObj, addressId, userId)
+	if err != nil {
+		return err
+	}
+	return recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_UPDATE_TYPE, addressId, before, after, debugInfo)
 }
 
-func isFirstAddress(userID string, debug *Debug) (bool, error) {
+func isFirstAddress(userID string, params *RequestParams, debug *Debug) (bool, error) {
 	// Use cache before using DB
-	var e QueryParams
-	addressList, _, cacheErr := getAddressListFromCache(userID, e, debug)
+	addressList, _, cacheErr := getAddressListFromCache(userID, params, debug)
 	if cacheErr != nil || len(addressList) == 0 {
-		db, _ := sqldb.Get("mysdb")
+		countMetric(appconstant.DB_FALLBACK_METRIC, 1, params, "operation:first_address")
+		db, _ := sqldb.GetWithContext(params.Context(), "mysdb")
 		prof := profiler.NewProfiler()
 		prof.StartProfile("AddressModel#isFirstAddress")
 
@@ -507,7 +635,7 @@ func isFirstAddress(userID string, debug *Debug) (bool, error) {
 			prof.EndProfileWithMetric([]string{"AddressModel#isFirstAddress"})
 		}()
 
-		sql := `SELECT COUNT(id_customer_address) FROM customer_address WHERE fk_customer = ?`
+		sql := `SELECT COUNT(id_customer_address) FROM customer_address WHERE fk_customer = ? AND deleted_at IS NULL`
 		debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "isFirstAddressSql", Value: sql + userID})
 		rows, err := db.Query(sql, userID)
 		if err != nil {
@@ -534,7 +662,7 @@ func isFirstAddress(userID string, debug *Debug) (bool, error) {
 func checkDefaultAddressInDB(addressID int, userID string, debugInfo *Debug) (int, error) {
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "checkDefaultAddressInDB", Value: "checkDefaultAddressInDB execute"})
 	db, _ := sqldb.Get("mysdb")
-	sql := "SELECT is_default_shipping,is_default_billing FROM customer_address WHERE id_customer_address=? AND fk_customer=?"
+	sql := "SELECT is_default_shipping,is_default_billing FROM customer_address WHERE id_customer_address=? AND fk_customer=? AND deleted_at IS NULL"
 	rows, err := db.Query(sql, addressID, userID)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Mysql Error while getting data from customer_address table |%s|%s|%s", appconstant.MYSQL_ERROR, err.Error(), "customer_address"))
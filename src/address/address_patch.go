@@ -0,0 +1,251 @@
+package address
+
+import (
+	"common/appconstant"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+var errAddressNotFound = errors.New("Address not found")
+
+//patchColumns maps the fields which can be patched to their customer_address column. The country
+//follows the region and sms opt-in is kept in customer_additional_info
+var patchColumns = map[string]string{
+	appconstant.FIRST_NAME:      "first_name",
+	appconstant.LAST_NAME:       "last_name",
+	appconstant.ADDRESS1:        "address1",
+	appconstant.ADDRESS2:        "address2",
+	appconstant.CITY:            "city",
+	appconstant.POSTCODE:        "postcode",
+	appconstant.ADDRESS_REGION:  "fk_customer_address_region",
+	appconstant.PHONE:           "phone",
+	appconstant.ALTERNATE_PHONE: "alternate_phone",
+	appconstant.IS_OFFICE:       "address_type",
+	appconstant.LABEL:           "label",
+	appconstant.LABEL_TYPE:      "label_type",
+	appconstant.HOUSE:           "house_number",
+	appconstant.BUILDING:        "building",
+	appconstant.STREET:          "street",
+	appconstant.LOCALITY:        "locality",
+	appconstant.LANDMARK:        "landmark",
+	appconstant.RECEIVER_NAME:   "receiver_name",
+	appconstant.RECEIVER_PHONE:  "receiver_phone",
+	appconstant.SMS_OPT:         "",
+}
+
+//patchClearableFields are the optional fields which a null in a PATCH body clears
+var patchClearableFields = map[string]bool{
+	appconstant.LAST_NAME:       true,
+	appconstant.ADDRESS2:        true,
+	appconstant.ALTERNATE_PHONE: true,
+	appconstant.IS_OFFICE:       true,
+	appconstant.LABEL:           true,
+	appconstant.HOUSE:           true,
+	appconstant.BUILDING:        true,
+	appconstant.STREET:          true,
+	appconstant.LOCALITY:        true,
+	appconstant.LANDMARK:        true,
+	appconstant.RECEIVER_NAME:   true,
+	appconstant.RECEIVER_PHONE:  true,
+}
+
+//getPatchValue returns the value a patched field is set to, nil to set the column to NULL. A cleared
+//name or address line is set to empty
+func getPatchValue(a AddressRequest, field string, set bool) interface{} {
+	switch field {
+	case appconstant.FIRST_NAME:
+		return a.FirstName
+	case appconstant.LAST_NAME:
+		return a.LastName
+	case appconstant.ADDRESS1:
+		return a.Address1
+	case appconstant.ADDRESS2:
+		return a.Address2
+	case appconstant.CITY:
+		return a.City
+	case appconstant.POSTCODE:
+		return a.PostCode
+	case appconstant.PHONE:
+		return a.EncryptedPhone
+	case appconstant.ALTERNATE_PHONE:
+		if !set || a.AlternatePhone == "" {
+			return nil
+		}
+		return a.EncryptedAlternatePhone
+	case appconstant.IS_OFFICE:
+		if !set {
+			return nil
+		}
+		return a.IsOffice
+	case appconstant.LABEL:
+		if !set || a.Label == "" {
+			return nil
+		}
+		return a.Label
+	case appconstant.LABEL_TYPE:
+		return a.LabelType
+	case appconstant.HOUSE, appconstant.BUILDING, appconstant.STREET, appconstant.LOCALITY, appconstant.LANDMARK:
+		if !set {
+			return nil
+		}
+		return getComponentValue(getAddressComponent(a, field))
+	case appconstant.RECEIVER_NAME:
+		if !set {
+			return nil
+		}
+		return getEncryptedValue(a.ReceiverName, a.EncryptedReceiverName)
+	case appconstant.RECEIVER_PHONE:
+		if !set {
+			return nil
+		}
+		return getEncryptedValue(a.ReceiverPhone, a.EncryptedReceiverPhone)
+	}
+	return nil
+}
+
+//patchAddressInDb writes only the fields present in the PATCH body. Cleared text fields are
+//emptied, cleared phone and address type are set to NULL
+func patchAddressInDb(params *RequestParams, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_model-patchAddress")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_model-patchAddress"})
+	}()
+
+	rc := params.RequestContext
+	userId := rc.UserID
+	addressId := strconv.Itoa(params.QueryParams.AddressId)
+	a := params.QueryParams.Address
+	patch := params.QueryParams.PatchFields
+
+	columns := make([]string, 0, len(patch)+3)
+	args := make([]interface{}, 0, len(patch)+4)
+	for field, set := range patch {
+		column := patchColumns[field]
+		switch field {
+		case appconstant.SMS_OPT:
+			continue
+		case appconstant.PHONE:
+			// The verification of the phone is reset if the phone changes, it has to come before the phone is set
+			columns = append(columns, "phone_verified = IF(phone <=> ?, phone_verified, 0)", column+" = ?")
+			args = append(args, a.EncryptedPhone, a.EncryptedPhone)
+		case appconstant.ADDRESS_REGION:
+			regionId, countryId, rerr := getRegionId(a.AddressRegion, debugInfo)
+			if rerr != nil {
+				return rerr
+			}
+			columns = append(columns, "fk_customer_address_region = ?", "fk_country = ?")
+			args = append(args, regionId, countryId)
+		default:
+			columns = append(columns, column+" = ?")
+			args = append(args, getPatchValue(a, field, set))
+		}
+	}
+
+	txObj, terr := db.GetTxnObj()
+	if terr != nil {
+		logger.Error(fmt.Sprintf("Transaction Error:: Error while patching user address |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
+	}
+	before, err1 := getAddressSnapshot(txObj, addressId, userId)
+	if err1 == nil && (before == nil || before["deleted_at"] != nil) {
+		err1 = errAddressNotFound
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		return err1
+	}
+	_, touchesAddress1 := patch[appconstant.ADDRESS1]
+	_, touchesAddress2 := patch[appconstant.ADDRESS2]
+	if !touchesAddress1 && !touchesAddress2 && patchesAddressComponents(patch) {
+		// Re-render the address lines of v1 clients from the stored and the patched components
+		components := mergeAddressComponents(a, patch, before)
+		a.Address1, a.Address2 = formatAddressLines(components)
+		if a.Address1 == "" {
+			txObj.Rollback()
+			return errors.New("Address can not be empty")
+		}
+		columns = append(columns, "address1 = ?", "address2 = ?")
+		args = append(args, a.Address1, a.Address2)
+		touchesAddress1, touchesAddress2 = true, true
+	}
+	if touchesAddress1 || touchesAddress2 {
+		address1, address2 := a.Address1, a.Address2
+		if !touchesAddress1 && before["address1"] != nil {
+			address1 = *before["address1"]
+		}
+		if !touchesAddress2 && before["address2"] != nil {
+			address2 = *before["address2"]
+		}
+		columns = append(columns, "validation_flag = ?")
+		args = append(args, getValidationFlag(params, address1+address2))
+	}
+	// The version is incremented even if only sms opt-in changes, as it is part of the address response
+	columns = append(columns, "version = version + 1")
+	condition, conditionArgs := versionCondition(params)
+	query := `UPDATE customer_address SET ` + strings.Join(columns, ", ") + ` WHERE fk_customer = ? AND id_customer_address = ? AND deleted_at IS NULL` + condition
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "patchAddressInDb:Sql", Value: query + "fk_customer: " + userId + "id_customer_address: " + addressId})
+	res, err1 := txObj.Exec(query, append(append(args, userId, addressId), conditionArgs...)...)
+	if err1 == nil {
+		err1 = checkVersionUpdated(params, res)
+	}
+	if _, ok := patch[appconstant.SMS_OPT]; ok && err1 == nil {
+		_, err1 = txObj.Exec(getUpdateSmsOptOfUserQuery(), a.SmsOpt, userId)
+	}
+	var after map[string]*string
+	if err1 == nil {
+		after, err1 = getAddressSnapshot(txObj, addressId, userId)
+	}
+	if err1 == nil {
+		err1 = recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_UPDATE, addressId, before, after, debugInfo)
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		logger.Error(fmt.Sprintf("Error while patching user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
+		return err1
+	}
+	err1 = txObj.Commit()
+	if err1 != nil {
+		txObj.Rollback()
+		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "Patch::CommitTransactionError:", Value: err1.Error()})
+		return err1
+	}
+	return nil
+}
+
+//patchesAddressComponents reports if the PATCH body sets or clears any structured component
+func patchesAddressComponents(patch map[string]bool) bool {
+	for _, field := range addressComponentFields {
+		if _, ok := patch[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+//mergeAddressComponents returns the structured components after the patch, taking the ones not in
+//the patch from the stored address
+func mergeAddressComponents(a AddressRequest, patch map[string]bool, stored map[string]*string) AddressRequest {
+	var merged AddressRequest
+	for _, field := range addressComponentFields {
+		value := getAddressComponent(a, field)
+		if _, ok := patch[field]; !ok {
+			value = ""
+			if v := stored[addressComponentColumns[field]]; v != nil {
+				value = *v
+			}
+		}
+		setAddressComponent(&merged, field, value)
+	}
+	return merged
+}
@@ -0,0 +1,61 @@
+package address
+
+import (
+	"common/appconstant"
+
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("PATCH address", func() {
+	a := AddressRequest{
+		FirstName:               "Asha",
+		Address1:                "12 MG Road",
+		City:                    "Pune",
+		PostCode:                "411001",
+		Phone:                   "9876543210",
+		EncryptedPhone:          "enc-phone",
+		AlternatePhone:          "9123456789",
+		EncryptedAlternatePhone: "enc-alt",
+		IsOffice:                "1",
+		Label:                   "Home",
+		LabelType:               appconstant.LABEL_TYPE_HOME,
+	}
+
+	gk.It("should set a patched field to its value, or clear it", func() {
+		tests := []struct {
+			address AddressRequest
+			field   string
+			set     bool
+			value   interface{}
+		}{
+			{a, appconstant.FIRST_NAME, true, "Asha"},
+			{a, appconstant.ADDRESS1, true, "12 MG Road"},
+			{a, appconstant.CITY, true, "Pune"},
+			{a, appconstant.POSTCODE, true, "411001"},
+			// The phone is stored encrypted
+			{a, appconstant.PHONE, true, "enc-phone"},
+			{a, appconstant.ALTERNATE_PHONE, true, "enc-alt"},
+			{a, appconstant.ALTERNATE_PHONE, false, nil},
+			{AddressRequest{}, appconstant.ALTERNATE_PHONE, true, nil},
+			{a, appconstant.IS_OFFICE, true, "1"},
+			{a, appconstant.IS_OFFICE, false, nil},
+			{a, appconstant.LABEL, true, "Home"},
+			{a, appconstant.LABEL, false, nil},
+			{AddressRequest{}, appconstant.LABEL, true, nil},
+			{a, appconstant.LABEL_TYPE, true, appconstant.LABEL_TYPE_HOME},
+			// A cleared name or address line is set to empty
+			{AddressRequest{}, appconstant.LAST_NAME, false, ""},
+			{AddressRequest{}, appconstant.ADDRESS2, false, ""},
+			{a, "Unknown", true, nil},
+		}
+		for _, test := range tests {
+			value := getPatchValue(test.address, test.field, test.set)
+			if test.value == nil {
+				gm.Expect(value).To(gm.BeNil(), test.field)
+			} else {
+				gm.Expect(value).To(gm.Equal(test.value), test.field)
+			}
+		}
+	})
+})
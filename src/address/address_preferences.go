@@ -0,0 +1,168 @@
+package address
+
+import (
+	"bytes"
+	"common/appconstant"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+//DeliveryPreferences are the delivery instructions of an address and when it can take deliveries
+type DeliveryPreferences struct {
+	Instructions    string           `json:"instructions"`
+	DeliveryWindows []DeliveryWindow `json:"delivery_windows"`
+	WeekendDelivery bool             `json:"weekend_delivery"`
+}
+
+//DeliveryWindow is a preferred time of the day for deliveries, in 24 hour HH:MM
+type DeliveryWindow struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+//defaultDeliveryPreferences are the preferences of an address the user has not set any for
+func defaultDeliveryPreferences() *DeliveryPreferences {
+	return &DeliveryPreferences{DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}
+}
+
+//newDeliveryPreferences builds the preferences from their customer_address_preference columns
+func newDeliveryPreferences(instructions string, deliveryWindows string, weekendDelivery string) *DeliveryPreferences {
+	prefs := defaultDeliveryPreferences()
+	prefs.Instructions = instructions
+	if deliveryWindows != "" {
+		if err := json.Unmarshal([]byte(deliveryWindows), &prefs.DeliveryWindows); err != nil {
+			logger.Warning(fmt.Sprintf("newDeliveryPreferences: Invalid delivery windows %s - %v", deliveryWindows, err))
+			prefs.DeliveryWindows = []DeliveryWindow{}
+		}
+	}
+	prefs.WeekendDelivery = weekendDelivery != "0"
+	return prefs
+}
+
+//parseDeliveryPreferences decodes and validates the preferences of a PUT body. The body replaces the
+//preferences, a missing weekend_delivery keeps the default of accepting weekend deliveries
+func parseDeliveryPreferences(body []byte) (*DeliveryPreferences, error) {
+	prefs := defaultDeliveryPreferences()
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(prefs); err != nil {
+		return nil, fmt.Errorf("Invalid delivery preferences - %v", err)
+	}
+	// Instructions are free text like labels, e.g. "Leave with security, don't ring the bell"
+	prefs.Instructions = sanitizeLabel(prefs.Instructions)
+	t := getTunables()
+	if len(prefs.Instructions) > t.maxInstructionsLength {
+		return nil, fmt.Errorf("Invalid value for field 'instructions' - length should be at most %d", t.maxInstructionsLength)
+	}
+	if prefs.DeliveryWindows == nil {
+		prefs.DeliveryWindows = []DeliveryWindow{}
+	}
+	if len(prefs.DeliveryWindows) > t.maxDeliveryWindows {
+		return nil, fmt.Errorf("At most %d delivery windows can be given", t.maxDeliveryWindows)
+	}
+	from := make([]time.Time, len(prefs.DeliveryWindows))
+	to := make([]time.Time, len(prefs.DeliveryWindows))
+	for i, window := range prefs.DeliveryWindows {
+		var ferr, terr error
+		from[i], ferr = time.Parse(appconstant.DELIVERY_WINDOW_FORMAT, window.From)
+		to[i], terr = time.Parse(appconstant.DELIVERY_WINDOW_FORMAT, window.To)
+		if ferr != nil || terr != nil {
+			return nil, errors.New("Delivery window should be given as from and to in HH:MM format")
+		}
+		if !from[i].Before(to[i]) {
+			return nil, fmt.Errorf("Delivery window %s-%s should end after it starts", window.From, window.To)
+		}
+		for j := 0; j < i; j++ {
+			if from[i].Before(to[j]) && from[j].Before(to[i]) {
+				return nil, fmt.Errorf("Delivery windows %s-%s and %s-%s overlap", window.From, window.To, prefs.DeliveryWindows[j].From, prefs.DeliveryWindows[j].To)
+			}
+		}
+	}
+	return prefs, nil
+}
+
+//saveAddressPreferencesInDb replaces the preferences of an address. The address version is incremented
+//so that the entity tags of the address and of the list change with its preferences
+func saveAddressPreferencesInDb(params *RequestParams, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_model-saveAddressPreferences")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_model-saveAddressPreferences"})
+	}()
+
+	rc := params.RequestContext
+	userId := rc.UserID
+	addressId := strconv.Itoa(params.QueryParams.AddressId)
+	prefs := params.QueryParams.Preferences
+	deliveryWindows, jerr := json.Marshal(prefs.DeliveryWindows)
+	if jerr != nil {
+		return jerr
+	}
+	weekendDelivery := 0
+	if prefs.WeekendDelivery {
+		weekendDelivery = 1
+	}
+
+	versionSql := `UPDATE customer_address SET version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? AND deleted_at IS NULL`
+	sql := `INSERT INTO customer_address_preference SET fk_customer_address = ?, instructions = ?, delivery_windows = ?, weekend_delivery = ?, updated_at = ?
+            ON DUPLICATE KEY UPDATE instructions = VALUES(instructions), delivery_windows = VALUES(delivery_windows), weekend_delivery = VALUES(weekend_delivery), updated_at = VALUES(updated_at)`
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "saveAddressPreferencesInDb:Sql", Value: sql + "fk_customer_address: " + addressId})
+
+	txObj, terr := db.GetTxnObj()
+	if terr != nil {
+		logger.Error(fmt.Sprintf("Transaction Error:: Error while saving address preferences |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
+	}
+	condition, conditionArgs := versionCondition(params)
+	res, err1 := txObj.Exec(versionSql+condition, append([]interface{}{addressId, userId}, conditionArgs...)...)
+	if err1 == nil {
+		if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+			//The address was found when the If-Match was checked
+			if err1 = checkVersionUpdated(params, res); err1 == nil {
+				err1 = errAddressNotFound
+			}
+		}
+	}
+	if err1 == nil {
+		_, err1 = txObj.Exec(sql, addressId, prefs.Instructions, string(deliveryWindows), weekendDelivery, time.Now().Format(appconstant.DATETIME_FORMAT))
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		if err1 != errAddressNotFound && err1 != errAddressChanged {
+			logger.Error(fmt.Sprintf("Error while saving address preferences |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address_preference"), rc)
+		}
+		return err1
+	}
+	err1 = txObj.Commit()
+	if err1 != nil {
+		txObj.Rollback()
+		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "SavePreferences::CommitTransactionError:", Value: err1.Error()})
+		return err1
+	}
+	return nil
+}
+
+//omitAddressPreferences drops the preferences from a list result whose client did not ask for them
+func omitAddressPreferences(addressList interface{}) {
+	switch v := addressList.(type) {
+	case map[string]*AddressResponse:
+		for _, a := range v {
+			a.Preferences = nil
+		}
+	case *AddressResponse:
+		if v != nil {
+			v.Preferences = nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package address
+
+import (
+	"common/appconstant"
+	"strings"
+
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Delivery preferences", func() {
+	gk.It("should accept only valid delivery preferences", func() {
+		tests := []struct {
+			name  string
+			body  string
+			valid bool
+			prefs *DeliveryPreferences
+		}{
+			{"empty", `{}`, true, &DeliveryPreferences{DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+			{"every preference", `{"instructions":"Leave with security","delivery_windows":[{"from":"09:00","to":"12:00"},{"from":"18:00","to":"21:00"}],"weekend_delivery":false}`, true,
+				&DeliveryPreferences{Instructions: "Leave with security", DeliveryWindows: []DeliveryWindow{{"09:00", "12:00"}, {"18:00", "21:00"}}}},
+			{"instructions sanitized", `{"instructions":"Don't ring the <bell>!"}`, true,
+				&DeliveryPreferences{Instructions: "Don't ring the bell", DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+			{"null windows", `{"delivery_windows":null}`, true, &DeliveryPreferences{DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+			{"adjacent windows", `{"delivery_windows":[{"from":"12:00","to":"14:00"},{"from":"09:00","to":"12:00"}]}`, true,
+				&DeliveryPreferences{DeliveryWindows: []DeliveryWindow{{"12:00", "14:00"}, {"09:00", "12:00"}}, WeekendDelivery: true}},
+			{"instructions too long", `{"instructions":"` + strings.Repeat("a", appconstant.MAX_INSTRUCTIONS_LENGTH+1) + `"}`, false, nil},
+			{"too many windows", `{"delivery_windows":[{"from":"08:00","to":"09:00"},{"from":"10:00","to":"11:00"},{"from":"12:00","to":"13:00"},{"from":"14:00","to":"15:00"}]}`, false, nil},
+			{"not HH:MM", `{"delivery_windows":[{"from":"9am","to":"12:00"}]}`, false, nil},
+			{"missing end", `{"delivery_windows":[{"from":"09:00"}]}`, false, nil},
+			{"hour out of range", `{"delivery_windows":[{"from":"09:00","to":"24:00"}]}`, false, nil},
+			{"ends before it starts", `{"delivery_windows":[{"from":"12:00","to":"09:00"}]}`, false, nil},
+			{"empty window", `{"delivery_windows":[{"from":"09:00","to":"09:00"}]}`, false, nil},
+			{"overlapping windows", `{"delivery_windows":[{"from":"09:00","to":"12:00"},{"from":"11:00","to":"13:00"}]}`, false, nil},
+			{"window within a window", `{"delivery_windows":[{"from":"10:00","to":"11:00"},{"from":"09:00","to":"12:00"}]}`, false, nil},
+			{"unknown field", `{"instruction":"Leave with security"}`, false, nil},
+			{"wrong type", `{"weekend_delivery":"no"}`, false, nil},
+			{"not json", `instructions`, false, nil},
+		}
+		for _, test := range tests {
+			prefs, err := parseDeliveryPreferences([]byte(test.body))
+			if !test.valid {
+				gm.Expect(err).NotTo(gm.BeNil(), test.name)
+				gm.Expect(prefs).To(gm.BeNil(), test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(prefs).To(gm.Equal(test.prefs), test.name)
+		}
+	})
+
+	gk.It("should read the stored delivery preferences", func() {
+		tests := []struct {
+			name            string
+			deliveryWindows string
+			weekendDelivery string
+			prefs           *DeliveryPreferences
+		}{
+			{"none stored", "", "", &DeliveryPreferences{Instructions: "Gate 2", DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+			{"stored", `[{"from":"09:00","to":"12:00"}]`, "0",
+				&DeliveryPreferences{Instructions: "Gate 2", DeliveryWindows: []DeliveryWindow{{"09:00", "12:00"}}}},
+			{"weekend delivery", "[]", "1", &DeliveryPreferences{Instructions: "Gate 2", DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+			// Windows that can't be read are dropped rather than failing the address
+			{"invalid windows", "{", "1", &DeliveryPreferences{Instructions: "Gate 2", DeliveryWindows: []DeliveryWindow{}, WeekendDelivery: true}},
+		}
+		for _, test := range tests {
+			gm.Expect(newDeliveryPreferences("Gate 2", test.deliveryWindows, test.weekendDelivery)).To(gm.Equal(test.prefs), test.name)
+		}
+	})
+})
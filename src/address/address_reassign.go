@@ -0,0 +1,177 @@
+package address
+
+import (
+	"common/appconstant"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+var errInvalidSuccessor = errors.New("The successor is not another address of the customer")
+
+//customerAddressDefaults is an address of the customer with its default flags, most recently used first
+type customerAddressDefaults struct {
+	id                string
+	isDefaultBilling  bool
+	isDefaultShipping bool
+}
+
+//deleteAddressWithReassign deletes an address and, if it is a default, makes the successor the default in its place.
+//The successor is the given address, else the most recently updated or created address of the customer. Deleting
+//the only address leaves the customer without defaults
+func deleteAddressWithReassign(params *RequestParams, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_model-deleteAddressWithReassign")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_model-deleteAddressWithReassign"})
+	}()
+
+	rc := params.RequestContext
+	userId := rc.UserID
+	addressId := strconv.Itoa(params.QueryParams.AddressId)
+	// The deleted address gives up its defaults, a deleted address is never a default
+	deleteQuery := `UPDATE customer_address SET deleted_at = ?, is_default_billing = 0, is_default_shipping = 0, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? AND deleted_at IS NULL`
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "deleteAddressWithReassign:Sql", Value: deleteQuery + "id_customer_address: " + addressId + "fk_customer: " + userId})
+
+	txObj, terr := db.GetTxnObj()
+	if terr != nil {
+		logger.Error(fmt.Sprintf("Transaction Error:: Error while deleting user address |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
+	}
+	addresses, err1 := lockCustomerAddresses(txObj, userId)
+	var deleted *customerAddressDefaults
+	var successor string
+	var reassigned []string
+	if err1 == nil {
+		deleted, successor, err1 = getSuccessor(addresses, addressId, strconv.Itoa(params.QueryParams.SuccessorId))
+	}
+	if err1 == nil {
+		err1 = softDeleteAddress(txObj, params, deleteQuery, addressId, debugInfo)
+	}
+	if err1 == nil && successor != "" {
+		columns := []string{}
+		if deleted.isDefaultBilling {
+			columns = append(columns, "is_default_billing = 1")
+			reassigned = append(reassigned, appconstant.BILLING)
+		}
+		if deleted.isDefaultShipping {
+			columns = append(columns, "is_default_shipping = 1")
+			reassigned = append(reassigned, appconstant.SHIPPING)
+		}
+		if len(columns) != 0 {
+			setQuery := `UPDATE customer_address SET ` + strings.Join(columns, ", ") + `, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ?`
+			err1 = changeAddressDefault(txObj, params, setQuery, successor, debugInfo)
+		}
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		if err1 != errAddressNotFound && err1 != errInvalidSuccessor && err1 != errAddressChanged {
+			logger.Error(fmt.Sprintf("Error while deleting user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
+		}
+		return err1
+	}
+	err1 = txObj.Commit()
+	if err1 != nil {
+		txObj.Rollback()
+		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "DeleteWithReassign::CommitTransactionError:", Value: err1.Error()})
+		return err1
+	}
+	for _, addressType := range reassigned {
+		countMetric(appconstant.DEFAULT_REASSIGNMENT_METRIC, 1, params, "type:"+addressType)
+	}
+	return nil
+}
+
+//lockCustomerAddresses locks the addresses of the customer and returns their default flags, most recently used first
+func lockCustomerAddresses(txObj *sql.Tx, userId string) ([]*customerAddressDefaults, error) {
+	query := `SELECT id_customer_address, is_default_billing, is_default_shipping FROM customer_address WHERE fk_customer = ? AND deleted_at IS NULL
+	          ORDER BY GREATEST(created_at, IFNULL(updated_at, created_at)) DESC, id_customer_address DESC FOR UPDATE`
+	rows, err := txObj.Query(query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	addresses := []*customerAddressDefaults{}
+	for rows.Next() {
+		var id, isBilling, isShipping string
+		if err = rows.Scan(&id, &isBilling, &isShipping); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, &customerAddressDefaults{id: id, isDefaultBilling: isBilling == "1", isDefaultShipping: isShipping == "1"})
+	}
+	return addresses, rows.Err()
+}
+
+//getSuccessor finds the address being deleted and the address to take its defaults, none if it has no defaults
+//or is the only address
+func getSuccessor(addresses []*customerAddressDefaults, addressId string, successorId string) (*customerAddressDefaults, string, error) {
+	var deleted, successor *customerAddressDefaults
+	for _, address := range addresses {
+		if address.id == addressId {
+			deleted = address
+		} else if successorId == "0" && successor == nil {
+			successor = address
+		} else if address.id == successorId {
+			successor = address
+		}
+	}
+	if deleted == nil {
+		return nil, "", errAddressNotFound
+	}
+	if successorId != "0" && successor == nil {
+		return nil, "", errInvalidSuccessor
+	}
+	if successor == nil || (!deleted.isDefaultBilling && !deleted.isDefaultShipping) {
+		return deleted, "", nil
+	}
+	return deleted, successor.id, nil
+}
+
+//softDeleteAddress marks an address deleted and records it in the history
+func softDeleteAddress(txObj *sql.Tx, params *RequestParams, query string, addressId string, debugInfo *Debug) error {
+	userId := params.RequestContext.UserID
+	before, err := getAddressSnapshot(txObj, addressId, userId)
+	if err != nil {
+		return err
+	}
+	// The deleted address is the one named by the If-Match of the request
+	condition, conditionArgs := versionCondition(params)
+	args := append([]interface{}{time.Now().Format(appconstant.DATETIME_FORMAT), addressId, userId}, conditionArgs...)
+	res, err := txObj.Exec(query+condition, args...)
+	if err != nil {
+		return err
+	}
+	if err = checkVersionUpdated(params, res); err != nil {
+		return err
+	}
+	after, err := getAddressSnapshot(txObj, addressId, userId)
+	if err != nil {
+		return err
+	}
+	return recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_DELETE, addressId, before, after, debugInfo)
+}
+
+//getDefaultAddresses picks the default billing and shipping addresses out of an address list
+func getDefaultAddresses(addressList map[string]*AddressResponse) *DefaultAddresses {
+	defaults := new(DefaultAddresses)
+	for _, address := range addressList {
+		if address.IsDefaultBilling == "1" {
+			defaults.Billing = address
+		}
+		if address.IsDefaultShipping == "1" {
+			defaults.Shipping = address
+		}
+	}
+	return defaults
+}
@@ -0,0 +1,53 @@
+package address
+
+import (
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Default reassignment", func() {
+	// The addresses are the most recently used first
+	addresses := []*customerAddressDefaults{
+		{id: "3"},
+		{id: "2", isDefaultBilling: true, isDefaultShipping: true},
+		{id: "1"},
+	}
+	billingOnly := []*customerAddressDefaults{
+		{id: "2", isDefaultBilling: true},
+		{id: "1", isDefaultShipping: true},
+	}
+
+	gk.It("should find the address to take the defaults of a deleted address", func() {
+		tests := []struct {
+			name        string
+			addresses   []*customerAddressDefaults
+			addressId   string
+			successorId string
+			deleted     string
+			successor   string
+			err         error
+		}{
+			{"most recently used", addresses, "2", "0", "2", "3", nil},
+			{"given successor", addresses, "2", "1", "2", "1", nil},
+			{"billing default", billingOnly, "2", "0", "2", "1", nil},
+			{"not a default", addresses, "3", "0", "3", "", nil},
+			{"not a default with successor", addresses, "1", "3", "1", "", nil},
+			{"only address", []*customerAddressDefaults{{id: "2", isDefaultShipping: true}}, "2", "0", "2", "", nil},
+			{"unknown address", addresses, "4", "0", "", "", errAddressNotFound},
+			{"unknown successor", addresses, "2", "4", "", "", errInvalidSuccessor},
+			{"successor is the deleted address", addresses, "2", "2", "", "", errInvalidSuccessor},
+			{"no addresses", nil, "2", "0", "", "", errAddressNotFound},
+		}
+		for _, test := range tests {
+			deleted, successor, err := getSuccessor(test.addresses, test.addressId, test.successorId)
+			if test.err != nil {
+				gm.Expect(err).To(gm.Equal(test.err), test.name)
+				gm.Expect(deleted).To(gm.BeNil(), test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(deleted.id).To(gm.Equal(test.deleted), test.name)
+			gm.Expect(successor).To(gm.Equal(test.successor), test.name)
+		}
+	})
+})
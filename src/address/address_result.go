@@ -3,10 +3,20 @@ package address
 type AddressResult struct {
 	Summary     AddressDetails `json:"Summary,omitempty"`
 	AddressList interface{}    `json:"AddressList,omitempty"`
+	DuplicateOf string         `json:"DuplicateOf,omitempty"`
+	//Defaults are the default addresses of the customer after a delete with reassign
+	Defaults *DefaultAddresses `json:"Defaults,omitempty"`
+}
+
+//DefaultAddresses are the default billing and shipping addresses of a customer
+type DefaultAddresses struct {
+	Billing  *AddressResponse `json:"billing,omitempty"`
+	Shipping *AddressResponse `json:"shipping,omitempty"`
 }
 
 type AddressDetails struct {
 	Count int    `json:"Count,omitempty"`
+	Total int    `json:"Total,omitempty"`
 	Type  string `json:"Type,omitempty"`
 }
 
@@ -14,12 +24,16 @@ type EncryptedFields struct {
 	Id                      string
 	EncryptedPhone          string
 	EncryptedAlternatePhone string
+	EncryptedReceiverName   string
+	EncryptedReceiverPhone  string
 }
 
 type DecryptedFields struct {
 	Id                      string
 	DecryptedPhone          string
 	DecryptedAlternatePhone string
+	DecryptedReceiverName   string
+	DecryptedReceiverPhone  string
 }
 
 type AddressRequest struct {
@@ -38,6 +52,17 @@ type AddressRequest struct {
 	EncryptedAlternatePhone string
 	PostCode                string
 	SmsOpt                  string `json:"Sms_opt"`
+	Label                   string
+	LabelType               string
+	House                   string
+	Building                string
+	Street                  string
+	Locality                string
+	Landmark                string
+	ReceiverName            string
+	ReceiverPhone           string
+	EncryptedReceiverName   string
+	EncryptedReceiverPhone  string
 }
 
 type AddressResponse struct {
@@ -59,5 +84,19 @@ type AddressResponse struct {
 	PostCode          string `json:"postcode"`
 	RegionName        string `json:"region_name"`
 	SmsOpt            string `json:"sms_opt"`
+	PhoneVerified     string `json:"phone_verified"`
+	Label             string `json:"label"`
+	LabelType         string `json:"label_type"`
+	House             string `json:"house"`
+	Building          string `json:"building"`
+	Street            string `json:"street"`
+	Locality          string `json:"locality"`
+	Landmark          string `json:"landmark"`
 	UpdatedAt         string `json:"updated_at"`
+	Version           string `json:"version"`
+	ETag              string `json:"etag"`
+	//Preferences are cached with the address, the list API returns them only when asked for
+	Preferences *DeliveryPreferences `json:"preferences,omitempty"`
+	//Receiver is the person the address ships to, when not the account holder
+	Receiver *ReceiverContact `json:"receiver,omitempty"`
 }
@@ -0,0 +1,143 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+var errAddressNotRestorable = errors.New("Address not found or the restore window has passed")
+
+//getSoftDeleteConfig returns the restore window and purge interval, falling back to the defaults
+func getSoftDeleteConfig() (restoreWindow time.Duration, purgeInterval time.Duration) {
+	restoreWindow = appconstant.DEFAULT_RESTORE_WINDOW_HOURS * time.Hour
+	purgeInterval = appconstant.DEFAULT_PURGE_INTERVAL_MINUTES * time.Minute
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil || appConfig.SoftDelete == nil {
+		return
+	}
+	if appConfig.SoftDelete.RestoreWindowHours > 0 {
+		restoreWindow = time.Duration(appConfig.SoftDelete.RestoreWindowHours) * time.Hour
+	}
+	if appConfig.SoftDelete.PurgeIntervalMinutes > 0 {
+		purgeInterval = time.Duration(appConfig.SoftDelete.PurgeIntervalMinutes) * time.Minute
+	}
+	return
+}
+
+//getRestoreCutoff is the time from which a deleted address can be restored, the addresses deleted before it are purged
+func getRestoreCutoff(now time.Time, restoreWindow time.Duration) string {
+	return now.Add(-restoreWindow).Format(appconstant.DATETIME_FORMAT)
+}
+
+//restoreAddress clears the deleted marker of an address deleted within the restore window
+func restoreAddress(params *RequestParams, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_model-restoreAddress")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_model-restoreAddress"})
+	}()
+
+	rc := params.RequestContext
+	userId := rc.UserID
+	addressId := strconv.Itoa(params.QueryParams.AddressId)
+	restoreWindow, _ := getSoftDeleteConfig()
+	deletedAfter := getRestoreCutoff(time.Now(), restoreWindow)
+
+	// A restored address is never a default, the customer may have another default by now
+	sql := `UPDATE customer_address SET deleted_at = NULL, is_default_billing = 0, is_default_shipping = 0, version = version + 1 WHERE id_customer_address=? AND fk_customer=? AND deleted_at >= ?`
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "restoreAddress:Sql", Value: sql + "id_customer_address: " + addressId + "fk_customer: " + userId})
+
+	txObj, terr := db.GetTxnObj()
+	if terr != nil {
+		logger.Error(fmt.Sprintf("Transaction Error:: Error while restoring user address |%s|%+v", appconstant.MYSQL_ERROR, terr), rc)
+		return terr
+	}
+	before, err1 := getAddressSnapshot(txObj, addressId, userId)
+	if err1 != nil {
+		txObj.Rollback()
+		return err1
+	}
+	restoreResult, err1 := txObj.Exec(sql, addressId, userId, deletedAfter)
+	if err1 != nil {
+		txObj.Rollback()
+		logger.Error(fmt.Sprintf("Error while restoring user address |%s|%s|%s", appconstant.MYSQL_ERROR, err1.Error(), "customer_address"), rc)
+		return err1
+	}
+	rowsaffected, _ := restoreResult.RowsAffected()
+	if rowsaffected == 0 {
+		txObj.Rollback()
+		return errAddressNotRestorable
+	}
+	after, err1 := getAddressSnapshot(txObj, addressId, userId)
+	if err1 == nil {
+		err1 = recordAddressChange(txObj, params, appconstant.HISTORY_ACTION_RESTORE, addressId, before, after, debugInfo)
+	}
+	if err1 != nil {
+		txObj.Rollback()
+		return err1
+	}
+	err1 = txObj.Commit()
+	if err1 != nil {
+		txObj.Rollback()
+		debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "Restore::CommitTransactionError:", Value: err1.Error()})
+		return err1
+	}
+	return nil
+}
+
+//purgeDeletedAddresses hard deletes, in batches, the addresses deleted before deletedBefore
+func purgeDeletedAddresses(db sqldb.SDBInterface, deletedBefore string) (int64, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("address-address_model-purgeDeletedAddresses")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"address-address_model-purgeDeletedAddresses"})
+	}()
+
+	sql := `DELETE FROM customer_address WHERE deleted_at IS NOT NULL AND deleted_at < ? LIMIT ` + strconv.Itoa(appconstant.PURGE_BATCH_SIZE)
+	var purged int64
+	for {
+		result, derr := db.Execute(sql, deletedBefore)
+		if derr != nil {
+			logger.Error(fmt.Sprintf("Error while purging deleted addresses |%s|%s|%s", appconstant.MYSQL_ERROR, derr.Error(), "customer_address"))
+			return purged, derr
+		}
+		rowsaffected, _ := result.RowsAffected()
+		purged += rowsaffected
+		if rowsaffected < appconstant.PURGE_BATCH_SIZE {
+			return purged, nil
+		}
+	}
+}
+
+//startPurgeJob periodically purges the deleted addresses whose restore window has passed
+func startPurgeJob() {
+	restoreWindow, purgeInterval := getSoftDeleteConfig()
+	ticker := time.NewTicker(purgeInterval)
+	go func() {
+		for range ticker.C {
+			db, derr := sqldb.Get("mysdb")
+			if derr != nil {
+				logger.Error(fmt.Sprintf("PurgeJob: Error while purging deleted addresses %v", derr))
+				continue
+			}
+			purged, err := purgeDeletedAddresses(db, getRestoreCutoff(time.Now(), restoreWindow))
+			if err != nil {
+				logger.Error(fmt.Sprintf("PurgeJob: Error while purging deleted addresses %v", err))
+				continue
+			}
+			logger.Info(fmt.Sprintf("PurgeJob: Purged %d deleted addresses", purged))
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jabong/florest-core/src/common/config"
+	"github.com/jabong/florest-core/src/components/sqldb"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//purgeResult is the result of a purge statement deleting a number of rows
+type purgeResult int64
+
+func (r purgeResult) LastInsertId() (int64, error) { return 0, nil }
+
+func (r purgeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+//purgeDB deletes the given numbers of rows, one per statement, and records the statements
+type purgeDB struct {
+	batches []int64
+	err     error
+	queries []string
+	args    [][]interface{}
+}
+
+func (d *purgeDB) Init(conf *sqldb.SDBConfig) *sqldb.SDBError { return nil }
+
+func (d *purgeDB) Query(string, ...interface{}) (*sql.Rows, *sqldb.SDBError) { return nil, nil }
+
+func (d *purgeDB) Execute(query string, args ...interface{}) (sql.Result, *sqldb.SDBError) {
+	d.queries = append(d.queries, query)
+	d.args = append(d.args, args)
+	if len(d.batches) == 0 {
+		return nil, &sqldb.SDBError{ErrCode: sqldb.ErrExecuteFailure, DeveloperMessage: d.err.Error()}
+	}
+	rows := d.batches[0]
+	d.batches = d.batches[1:]
+	return purgeResult(rows), nil
+}
+
+func (d *purgeDB) Ping() *sqldb.SDBError { return nil }
+
+func (d *purgeDB) Close() *sqldb.SDBError { return nil }
+
+func (d *purgeDB) GetTxnObj() (*sql.Tx, *sqldb.SDBError) { return nil, nil }
+
+var _ = gk.Describe("Soft delete", func() {
+	var previous interface{}
+
+	gk.BeforeEach(func() {
+		previous = config.GlobalAppConfig.ApplicationConfig
+	})
+
+	gk.AfterEach(func() {
+		config.GlobalAppConfig.ApplicationConfig = previous
+	})
+
+	gk.It("should restore and purge with the configured window", func() {
+		tests := []struct {
+			name          string
+			conf          *appconfig.SoftDeleteConfig
+			restoreWindow time.Duration
+			purgeInterval time.Duration
+		}{
+			{"not configured", nil, appconstant.DEFAULT_RESTORE_WINDOW_HOURS * time.Hour,
+				appconstant.DEFAULT_PURGE_INTERVAL_MINUTES * time.Minute},
+			{"configured", &appconfig.SoftDeleteConfig{RestoreWindowHours: 24, PurgeIntervalMinutes: 15},
+				24 * time.Hour, 15 * time.Minute},
+			{"window only", &appconfig.SoftDeleteConfig{RestoreWindowHours: 1}, time.Hour,
+				appconstant.DEFAULT_PURGE_INTERVAL_MINUTES * time.Minute},
+			{"not positive", &appconfig.SoftDeleteConfig{RestoreWindowHours: -1}, appconstant.DEFAULT_RESTORE_WINDOW_HOURS * time.Hour,
+				appconstant.DEFAULT_PURGE_INTERVAL_MINUTES * time.Minute},
+		}
+		for _, test := range tests {
+			config.GlobalAppConfig.ApplicationConfig = &appconfig.AddressServiceConfig{SoftDelete: test.conf}
+			restoreWindow, purgeInterval := getSoftDeleteConfig()
+			gm.Expect(restoreWindow).To(gm.Equal(test.restoreWindow), test.name)
+			gm.Expect(purgeInterval).To(gm.Equal(test.purgeInterval), test.name)
+		}
+	})
+
+	gk.It("should restore the addresses deleted within the window", func() {
+		now := time.Date(2026, 10, 19, 12, 30, 0, 0, time.Local)
+		gm.Expect(getRestoreCutoff(now, 72*time.Hour)).To(gm.Equal("2026-10-16 12:30:00"))
+		gm.Expect(getRestoreCutoff(now, time.Hour)).To(gm.Equal("2026-10-19 11:30:00"))
+	})
+
+	gk.It("should purge the addresses deleted before the window in batches", func() {
+		tests := []struct {
+			name       string
+			batches    []int64
+			err        error
+			purged     int64
+			statements int
+		}{
+			{"nothing to purge", []int64{0}, nil, 0, 1},
+			{"one batch", []int64{12}, nil, 12, 1},
+			{"full batches", []int64{appconstant.PURGE_BATCH_SIZE, appconstant.PURGE_BATCH_SIZE, 3}, nil,
+				2*appconstant.PURGE_BATCH_SIZE + 3, 3},
+			{"failing batch", []int64{appconstant.PURGE_BATCH_SIZE}, errors.New("lock wait timeout"),
+				appconstant.PURGE_BATCH_SIZE, 2},
+		}
+		for _, test := range tests {
+			db := &purgeDB{batches: test.batches, err: test.err}
+			purged, err := purgeDeletedAddresses(db, "2026-10-16 12:30:00")
+			if test.err != nil {
+				gm.Expect(err).NotTo(gm.BeNil(), test.name)
+			} else {
+				gm.Expect(err).To(gm.BeNil(), test.name)
+			}
+			gm.Expect(purged).To(gm.Equal(test.purged), test.name)
+			gm.Expect(db.queries).To(gm.HaveLen(test.statements), test.name)
+			for i, query := range db.queries {
+				// Only the deleted addresses past the window are purged
+				gm.Expect(strings.HasPrefix(query, "DELETE FROM customer_address WHERE deleted_at IS NOT NULL AND deleted_at < ?")).To(gm.BeTrue(), test.name)
+				gm.Expect(db.args[i]).To(gm.Equal([]interface{}{"2026-10-16 12:30:00"}), test.name)
+			}
+		}
+	})
+})
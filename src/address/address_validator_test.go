@@ -0,0 +1,170 @@
+package address
+
+import (
+	"common/appconstant"
+	"net/http"
+	"strings"
+
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//validAddressBody is the body of a request creating an address, with the given fields added
+func validAddressBody(fields string) string {
+	body := `{"FirstName":"Asha","Address1":"12 MG Road","City":"Pune","PostCode":"411001","AddressRegion":"12"`
+	if fields != "" {
+		body += "," + fields
+	}
+	return body + "}"
+}
+
+//validateRequest validates the body of an address request and gets the params read from it
+func validateRequest(httpVerb utilHttp.Method, body string) (*RequestParams, error) {
+	var io workflow.WorkFlowData
+	io.Create(new(workflow.WorkFlowIOInMemoryImpl), new(workflow.WorkFlowECInMemoryImpl))
+	req, _ := http.NewRequest(string(httpVerb), "/address/V1/address/", strings.NewReader(body))
+	io.IOData.Set(appconstant.IO_HTTP_REQUEST, &utilHttp.Request{HTTPVerb: httpVerb, OriginalRequest: req})
+	params := &RequestParams{}
+	err := validateAddressParams(params, httpVerb, io)
+	return params, err
+}
+
+//validateBody validates the body of an address request and gets the address read from it
+func validateBody(httpVerb utilHttp.Method, body string) (AddressRequest, error) {
+	params, err := validateRequest(httpVerb, body)
+	return params.QueryParams.Address, err
+}
+
+//expectFieldError expects the validation to fail the rule on the field
+func expectFieldError(err error, field string, rule string, name string) {
+	fe, ok := err.(*fieldError)
+	gm.Expect(ok).To(gm.BeTrue(), name)
+	gm.Expect(fe.field).To(gm.Equal(field), name)
+	gm.Expect(fe.rule).To(gm.Equal(rule), name)
+}
+
+var _ = gk.Describe("Address validator", func() {
+	gk.It("should accept only a label of the maximum length", func() {
+		tests := []struct {
+			name  string
+			label string
+			rule  string
+			value string
+		}{
+			{"label", `"Home"`, "", "Home"},
+			{"illegal characters", `"Mom's <house>!"`, "", "Mom's house"},
+			{"maximum length", `"` + strings.Repeat("a", appconstant.MAX_LABEL_LENGTH) + `"`, "", strings.Repeat("a", appconstant.MAX_LABEL_LENGTH)},
+			// The length is checked once the label is sanitized
+			{"illegal characters past the maximum length", `"` + strings.Repeat("a", appconstant.MAX_LABEL_LENGTH) + `!!"`, "", strings.Repeat("a", appconstant.MAX_LABEL_LENGTH)},
+			{"too long", `"` + strings.Repeat("a", appconstant.MAX_LABEL_LENGTH+1) + `"`, appconstant.RULE_LENGTH, ""},
+			{"not a string", `12`, appconstant.RULE_TYPE, ""},
+		}
+		for _, test := range tests {
+			address, err := validateBody(utilHttp.POST, validAddressBody(`"Label":`+test.label))
+			if test.rule != "" {
+				expectFieldError(err, appconstant.LABEL, test.rule, test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(address.Label).To(gm.Equal(test.value), test.name)
+		}
+	})
+
+	gk.It("should accept only a known label type and keep the office flag consistent with it", func() {
+		tests := []struct {
+			name      string
+			fields    string
+			valid     bool
+			labelType string
+			isOffice  string
+		}{
+			{"home", `"LabelType":"home"`, true, appconstant.LABEL_TYPE_HOME, "0"},
+			{"work", `"LabelType":"work"`, true, appconstant.LABEL_TYPE_WORK, "1"},
+			{"other", `"LabelType":"other"`, true, appconstant.LABEL_TYPE_OTHER, "0"},
+			// Clients sending only the office flag get the matching label type
+			{"office only", `"AddressType":"1"`, true, appconstant.LABEL_TYPE_WORK, "1"},
+			{"home only", `"AddressType":"0"`, true, appconstant.LABEL_TYPE_HOME, "0"},
+			{"neither", ``, true, appconstant.LABEL_TYPE_HOME, ""},
+			{"both", `"LabelType":"other","AddressType":"1"`, true, appconstant.LABEL_TYPE_OTHER, "1"},
+			{"unknown", `"LabelType":"office"`, false, "", ""},
+			{"case", `"LabelType":"Home"`, false, "", ""},
+			{"not a string", `"LabelType":1`, false, "", ""},
+		}
+		for _, test := range tests {
+			address, err := validateBody(utilHttp.POST, validAddressBody(test.fields))
+			if !test.valid {
+				expectFieldError(err, appconstant.LABEL_TYPE, appconstant.RULE_VALUE, test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(address.LabelType).To(gm.Equal(test.labelType), test.name)
+			gm.Expect(address.IsOffice).To(gm.Equal(test.isOffice), test.name)
+		}
+	})
+
+	gk.It("should write the label type and the office flag together in a PATCH", func() {
+		tests := []struct {
+			name   string
+			fields string
+			patch  map[string]bool
+		}{
+			{"label type", `"LabelType":"work"`, map[string]bool{appconstant.LABEL_TYPE: true, appconstant.IS_OFFICE: true}},
+			{"office flag", `"AddressType":"0"`, map[string]bool{appconstant.LABEL_TYPE: true, appconstant.IS_OFFICE: true}},
+			{"label", `"Label":"Gym"`, map[string]bool{appconstant.LABEL: true}},
+		}
+		for _, test := range tests {
+			params, err := validateRequest(utilHttp.PATCH, "{"+test.fields+"}")
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(params.QueryParams.PatchFields).To(gm.Equal(test.patch), test.name)
+		}
+	})
+
+	gk.It("should accept a receiver only with both a name and a phone", func() {
+		tests := []struct {
+			name     string
+			httpVerb utilHttp.Method
+			fields   string
+			field    string
+			rule     string
+			receiver *ReceiverContact
+		}{
+			{"no receiver", utilHttp.POST, ``, "", "", nil},
+			{"receiver", utilHttp.POST, `"ReceiverName":"Ravi Kumar","ReceiverPhone":"9123456789"`, "", "",
+				&ReceiverContact{Name: "Ravi Kumar", Phone: "9123456789"}},
+			{"receiver of a replaced address", utilHttp.PUT, `"ReceiverName":"Ravi","ReceiverPhone":"9123456789"`, "", "",
+				&ReceiverContact{Name: "Ravi", Phone: "9123456789"}},
+			{"name only", utilHttp.POST, `"ReceiverName":"Ravi"`, appconstant.RECEIVER_PHONE, appconstant.RULE_TOGETHER, nil},
+			{"phone only", utilHttp.PUT, `"ReceiverPhone":"9123456789"`, appconstant.RECEIVER_NAME, appconstant.RULE_TOGETHER, nil},
+			{"empty phone", utilHttp.POST, `"ReceiverName":"Ravi","ReceiverPhone":""`, appconstant.RECEIVER_PHONE, appconstant.RULE_TOGETHER, nil},
+			{"phone too short", utilHttp.POST, `"ReceiverName":"Ravi","ReceiverPhone":"91234"`, appconstant.RECEIVER_PHONE, appconstant.RULE_LENGTH, nil},
+			{"phone not digits", utilHttp.POST, `"ReceiverName":"Ravi","ReceiverPhone":"91234-56789"`, appconstant.RECEIVER_PHONE, appconstant.RULE_TYPE, nil},
+			{"name not a string", utilHttp.POST, `"ReceiverName":1,"ReceiverPhone":"9123456789"`, appconstant.RECEIVER_NAME, appconstant.RULE_TYPE, nil},
+		}
+		for _, test := range tests {
+			address, err := validateBody(test.httpVerb, validAddressBody(test.fields))
+			if test.rule != "" {
+				expectFieldError(err, test.field, test.rule, test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(newReceiverContact(address.ReceiverName, address.ReceiverPhone)).To(gm.Equal(test.receiver), test.name)
+		}
+	})
+
+	gk.It("should patch the receiver name and phone on their own", func() {
+		params, err := validateRequest(utilHttp.PATCH, `{"ReceiverName":"Ravi"}`)
+		gm.Expect(err).To(gm.BeNil())
+		gm.Expect(params.QueryParams.PatchFields).To(gm.Equal(map[string]bool{appconstant.RECEIVER_NAME: true}))
+
+		params, err = validateRequest(utilHttp.PATCH, `{"ReceiverName":null,"ReceiverPhone":null}`)
+		gm.Expect(err).To(gm.BeNil())
+		gm.Expect(params.QueryParams.PatchFields).To(gm.Equal(map[string]bool{appconstant.RECEIVER_NAME: false, appconstant.RECEIVER_PHONE: false}))
+	})
+
+	gk.It("should store only the receiver fields given", func() {
+		gm.Expect(getEncryptedValue("9123456789", "enc-receiver")).To(gm.Equal("enc-receiver"))
+		gm.Expect(getEncryptedValue("", "")).To(gm.BeNil())
+	})
+})
@@ -0,0 +1,114 @@
+package address
+
+import (
+	"common/appconstant"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AdminAddressExecutor lists, shows, edits and deletes the addresses of any customer on behalf
+//of a support agent. Every action is audited before it is carried out
+type AdminAddressExecutor struct {
+	id string
+}
+
+func (n *AdminAddressExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n AdminAddressExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n AdminAddressExecutor) Name() string {
+	return "AdminAddressExecutor"
+}
+
+func (n AdminAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AdminAddressExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AdminAddressExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Admin Address Executor", "Admin Address Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil || params.Admin == nil {
+		logger.Error("AdminAddressExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+
+	action := getAdminAction(appHTTPReq.HTTPVerb, params.QueryParams.AddressId)
+	debugInfo := new(Debug)
+	err := addAdminAudit(newAdminAudit(params, action), debugInfo)
+	if err != nil {
+		addDebugContents(io, debugInfo)
+		logger.Error(fmt.Sprintf("AdminAddressExecutor: could not audit %s by agent %s - %v", action, params.Admin.AgentId, err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: "Could not record the admin audit"}
+	}
+
+	var addressResult *AddressResult
+	switch action {
+	case appconstant.ADMIN_ACTION_LIST:
+		addressResult, err = GetAddressList(params, debugInfo)
+	case appconstant.ADMIN_ACTION_VIEW:
+		addressResult, err = getAdminAddress(params, debugInfo)
+	case appconstant.ADMIN_ACTION_UPDATE:
+		addressResult, err = UpdateAddress(params, debugInfo)
+	case appconstant.ADMIN_ACTION_DELETE:
+		addressResult, err = DeleteAddress(params, debugInfo)
+	}
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("AdminAddressExecutor: %s by agent %s failed - %v", action, params.Admin.AgentId, err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting admin address result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
+
+func getAdminAction(httpVerb utilHttp.Method, addressID int) string {
+	switch httpVerb {
+	case utilHttp.PUT:
+		return appconstant.ADMIN_ACTION_UPDATE
+	case utilHttp.DELETE:
+		return appconstant.ADMIN_ACTION_DELETE
+	}
+	if addressID != 0 {
+		return appconstant.ADMIN_ACTION_VIEW
+	}
+	return appconstant.ADMIN_ACTION_LIST
+}
+
+//getAdminAddress gets a single address of the customer with the phones decrypted
+func getAdminAddress(params *RequestParams, debugInfo *Debug) (*AddressResult, error) {
+	a := new(AddressResult)
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	addressList, _, err := getAddressList(params, addressID, debugInfo)
+	if err != nil {
+		return a, err
+	}
+	address, ok := addressList[addressID]
+	if !ok {
+		return a, errors.New("Address not found")
+	}
+	a.AddressList = address
+	a.Summary = AddressDetails{Count: 1, Type: appconstant.ALL}
+	return a, nil
+}
@@ -0,0 +1,60 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+	"strconv"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+//AdminAudit is a record of an action taken by a support agent on a customer's address book
+type AdminAudit struct {
+	AgentId    string
+	Action     string
+	CustomerId string
+	AddressId  int
+	Reason     string
+	RequestId  string
+}
+
+//newAdminAudit creates the audit record of action for the admin request described by params
+func newAdminAudit(params *RequestParams, action string) AdminAudit {
+	return AdminAudit{
+		AgentId:    params.Admin.AgentId,
+		Action:     action,
+		CustomerId: params.RequestContext.UserID,
+		AddressId:  params.QueryParams.AddressId,
+		Reason:     params.Admin.Reason,
+		RequestId:  params.RequestContext.RequestID,
+	}
+}
+
+//addAdminAudit inserts the audit record in customer_address_admin_audit
+func addAdminAudit(audit AdminAudit, debug *Debug) error {
+	db, err := sqldb.Get("mysdb")
+	if err != nil {
+		return err
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AdminAudit#addAdminAudit")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AdminAudit#addAdminAudit"})
+	}()
+
+	var addressID interface{}
+	if audit.AddressId != 0 {
+		addressID = strconv.Itoa(audit.AddressId)
+	}
+	sql := `INSERT INTO customer_address_admin_audit SET agent_id=?, action=?, fk_customer=?, fk_customer_address=?, reason=?, request_id=?, created_at=?`
+	debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "addAdminAudit:Sql", Value: sql + fmt.Sprintf("%+v", audit)})
+	_, derr := db.Execute(sql, audit.AgentId, audit.Action, audit.CustomerId, addressID, audit.Reason, audit.RequestId, time.Now().Format(appconstant.DATETIME_FORMAT))
+	if derr != nil {
+		logger.Error(fmt.Sprintf("Error while inserting admin audit |%s|%s|%s", appconstant.MYSQL_ERROR, derr.Error(), "customer_address_admin_audit"))
+		return derr
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AdminAuthenticator checks the agent credentials for the admin APIs and
+//builds the request params for the customer being acted upon
+type AdminAuthenticator struct {
+	id string
+}
+
+func (a *AdminAuthenticator) SetID(id string) {
+	a.id = id
+}
+
+func (a AdminAuthenticator) GetID() (id string, err error) {
+	return a.id, nil
+}
+
+func (a AdminAuthenticator) Name() string {
+	return "AdminAuthenticator"
+}
+
+func (a AdminAuthenticator) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	p := profiler.NewProfiler()
+	p.StartProfile("Address#AdminAuthenticator")
+
+	defer func() {
+		p.EndProfileWithMetric([]string{"AdminAuthenticator#Execute"})
+	}()
+
+	hrc, _ := io.ExecContext.Get(constants.RequestContext)
+	rc, _ := hrc.(utilHttp.RequestContext)
+	logger.Info("Entered "+a.Name(), rc)
+	io.ExecContext.SetDebugMsg("Admin Authenticator", "Admin Authenticator Execute")
+
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, pOk := rp.(*utilHttp.Request)
+	if !pOk || appHTTPReq == nil {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "Invalid request params"}
+	}
+
+	agentID := strings.TrimSpace(appHTTPReq.GetHeaderParameter(appconstant.AGENT_ID))
+	if agentID == "" || appHTTPReq.Headers.AuthToken == "" {
+		return io, &constants.AppError{Code: appconstant.AdminUnauthorizedErrorCode, Message: "AgentId and Token must be provided in request header"}
+	}
+	if err := authenticateAgent(agentID, appHTTPReq.Headers.AuthToken); err != nil {
+		logger.Error(fmt.Sprintf("AdminAuthenticator: agent %s rejected - %v", agentID, err), rc)
+		return io, &constants.AppError{Code: appconstant.AdminForbiddenErrorCode, Message: err.Error()}
+	}
+	reason := strings.TrimSpace(appHTTPReq.GetHeaderParameter(appconstant.ADMIN_REASON))
+	if reason == "" {
+		return io, &constants.AppError{Code: constants.ParamsInSufficientErrorCode, Message: "Reason must be provided in request header"}
+	}
+	if rc.RequestID == "" {
+		return io, &constants.AppError{Code: constants.ParamsInSufficientErrorCode, Message: "RequestId must be provided in request header"}
+	}
+
+	m, _ := io.IOData.Get(constants.ResponseMetaData)
+	md, _ := m.(*utilHttp.ResponseMetaData)
+	if md == nil {
+		md = utilHttp.NewResponseMetaData()
+		io.IOData.Set(constants.ResponseMetaData, md)
+	}
+
+	params := RequestParams{}
+	updateParamsWithBuckets(&params, io)
+	updateParamsWithRequestContext(&params, io)
+	params.Admin = &AdminContext{AgentId: agentID, Reason: reason}
+	// The admin APIs not acting on a customer, like the workflow graphs, have no customer in the path
+	if isCustomerAdminAPI(appHTTPReq) {
+		if err := validateAndSetAdminParams(&params, appHTTPReq); err != nil {
+			return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+		}
+		logger.Info(fmt.Sprintf("AdminAuthenticator: agent %s acting on customer %s", agentID, params.RequestContext.UserID), rc)
+	} else {
+		logger.Info(fmt.Sprintf("AdminAuthenticator: agent %s authenticated for %s", agentID, appHTTPReq.URI), rc)
+	}
+	if derr := io.IOData.Set(appconstant.IO_REQUEST_PARAMS, &params); derr != nil {
+		return io, derr
+	}
+	return io, nil
+}
+
+//isCustomerAdminAPI tells if the admin API acts on the addresses of a customer
+func isCustomerAdminAPI(httpReq *utilHttp.Request) bool {
+	if httpReq.PathParameters == nil {
+		return false
+	}
+	_, found := (*httpReq.PathParameters)[appconstant.URLPARAM_CUSTOMERID]
+	return found
+}
+
+//authenticateAgent checks the token of the agent and that the agent holds the admin role
+func authenticateAgent(agentID string, token string) error {
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil {
+		return err
+	}
+	if appConfig.Admin == nil {
+		return errors.New("Admin access is not configured")
+	}
+	for _, agent := range appConfig.Admin.Agents {
+		if agent == nil || agent.AgentId != agentID {
+			continue
+		}
+		agentToken := getAgentToken(appConfig.Admin, agentID)
+		if agentToken == "" {
+			return errors.New("No token is configured for the agent")
+		}
+		if subtle.ConstantTimeCompare([]byte(agentToken), []byte(token)) != 1 {
+			return errors.New("Invalid agent credentials")
+		}
+		for _, role := range agent.Roles {
+			if role == appConfig.Admin.Role {
+				return nil
+			}
+		}
+		return errors.New("Agent is not allowed to manage customer addresses")
+	}
+	return errors.New("Invalid agent credentials")
+}
+
+//getAgentToken gets the token of the agent from the agent tokens of the admin config, empty if it has none
+func getAgentToken(admin *appconfig.AdminConfig, agentID string) string {
+	for _, pair := range strings.Split(admin.AgentTokens, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(kv) == 2 && kv[0] == agentID {
+			return kv[1]
+		}
+	}
+	return ""
+}
+
+//validateAndSetAdminParams reads the target customer and address from the path. The customer
+//being acted upon is set as the user of the request context so the accessors can be reused
+func validateAndSetAdminParams(params *RequestParams, httpReq *utilHttp.Request) error {
+	customerID := httpReq.GetPathParameter(appconstant.URLPARAM_CUSTOMERID)
+	if _, err := strconv.Atoi(customerID); err != nil {
+		return errors.New("CustomerId is missing or not a number")
+	}
+	params.RequestContext.UserID = customerID
+	params.QueryParams.AddressType = appconstant.ALL
+
+	val := httpReq.GetPathParameter(appconstant.URLPARAM_ADDRESSID)
+	if val == "" {
+		if httpReq.HTTPVerb != utilHttp.GET {
+			return errors.New("Id is missing or not a number")
+		}
+		return nil
+	}
+	addressID, err := strconv.Atoi(val)
+	if err != nil {
+		return errors.New("Id is missing or not a number")
+	}
+	params.QueryParams.AddressId = addressID
+	if httpReq.HTTPVerb == utilHttp.DELETE {
+		return validateAndSetReassignParams(params, httpReq)
+	}
+	return nil
+}
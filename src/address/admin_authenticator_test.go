@@ -0,0 +1,62 @@
+package address
+
+import (
+	"common/appconfig"
+
+	"github.com/jabong/florest-core/src/common/config"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Admin agent tokens", func() {
+	admin := &appconfig.AdminConfig{
+		Role: "address_admin",
+		Agents: []*appconfig.AdminAgent{
+			{AgentId: "cs-agent", Roles: []string{"address_admin"}},
+			{AgentId: "no-token", Roles: []string{"address_admin"}},
+			{AgentId: "viewer", Roles: []string{"address_viewer"}},
+		},
+		AgentTokens: "cs-agent:secret, viewer:view:er",
+	}
+
+	gk.It("should get the token of an agent", func() {
+		tests := []struct {
+			agentID string
+			token   string
+		}{
+			{"cs-agent", "secret"},
+			{"viewer", "view:er"},
+			{"no-token", ""},
+			{"unknown", ""},
+			{"", ""},
+		}
+		for _, test := range tests {
+			gm.Expect(getAgentToken(admin, test.agentID)).To(gm.Equal(test.token), test.agentID)
+		}
+	})
+
+	gk.It("should refuse agents without a token", func() {
+		previous := config.GlobalAppConfig.ApplicationConfig
+		defer func() {
+			config.GlobalAppConfig.ApplicationConfig = previous
+		}()
+		config.GlobalAppConfig.ApplicationConfig = &appconfig.AddressServiceConfig{Admin: admin}
+
+		tests := []struct {
+			agentID string
+			token   string
+			allowed bool
+		}{
+			{"cs-agent", "secret", true},
+			{"cs-agent", "wrong", false},
+			{"cs-agent", "", false},
+			{"no-token", "", false},
+			{"viewer", "view:er", false},
+			{"unknown", "", false},
+		}
+		for _, test := range tests {
+			err := authenticateAgent(test.agentID, test.token)
+			gm.Expect(err == nil).To(gm.Equal(test.allowed), test.agentID+":"+test.token)
+		}
+	})
+})
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AdminDeleteAddressAPI struct {
+}
+
+func (a *AdminDeleteAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADMIN",
+		Version:  "V1",
+		Action:   "DELETE",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "address/{" + appconstant.URLPARAM_CUSTOMERID + "}/{" + appconstant.URLPARAM_ADDRESSID + "}",
+	}
+}
+
+func (a *AdminDeleteAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AdminDeleteAddress")
+}
+
+func (a *AdminDeleteAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AdminDeleteAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AdminDeleteAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
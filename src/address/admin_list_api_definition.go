@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AdminListAddressAPI struct {
+}
+
+func (a *AdminListAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADMIN",
+		Version:  "V1",
+		Action:   "GET",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "address/{" + appconstant.URLPARAM_CUSTOMERID + "}",
+	}
+}
+
+func (a *AdminListAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AdminListAddress")
+}
+
+func (a *AdminListAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AdminListAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AdminListAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
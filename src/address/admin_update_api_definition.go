@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AdminUpdateAddressAPI struct {
+}
+
+func (a *AdminUpdateAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADMIN",
+		Version:  "V1",
+		Action:   "PUT",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "address/{" + appconstant.URLPARAM_CUSTOMERID + "}/{" + appconstant.URLPARAM_ADDRESSID + "}",
+	}
+}
+
+func (a *AdminUpdateAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AdminUpdateAddress")
+}
+
+func (a *AdminUpdateAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AdminUpdateAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AdminUpdateAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AdminViewAddressAPI struct {
+}
+
+func (a *AdminViewAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADMIN",
+		Version:  "V1",
+		Action:   "GET",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "address/{" + appconstant.URLPARAM_CUSTOMERID + "}/{" + appconstant.URLPARAM_ADDRESSID + "}",
+	}
+}
+
+func (a *AdminViewAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AdminViewAddress")
+}
+
+func (a *AdminViewAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AdminViewAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AdminViewAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -0,0 +1,38 @@
+package address
+
+import (
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AdminWorkflowsAPI struct {
+}
+
+func (a *AdminWorkflowsAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADMIN",
+		Version:  "V1",
+		Action:   "GET",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "workflows",
+	}
+}
+
+func (a *AdminWorkflowsAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AdminWorkflows")
+}
+
+func (a *AdminWorkflowsAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AdminWorkflowsAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AdminWorkflowsAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type ConfirmPhoneAPI struct {
+}
+
+func (a *ConfirmPhoneAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "POST",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/phone/confirm",
+	}
+}
+
+func (a *ConfirmPhoneAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("ConfirmPhone")
+}
+
+func (a *ConfirmPhoneAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *ConfirmPhoneAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *ConfirmPhoneAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -42,15 +42,37 @@ func (a DataEncryptor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData,
 		logger.Error("DataEncryptor. invalid type of params")
 		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
 	}
+	// A PATCH body may carry neither phone
+	address := &params.QueryParams.Address
+	patch := params.QueryParams.PatchFields
+	_, patchesPhone := patch[appconstant.PHONE]
+	encryptPhone := patch == nil || patchesPhone
 	var phoneStr []string
-	phoneStr = append(phoneStr, params.QueryParams.Address.Phone)
+	var encrypted []*string
+	if encryptPhone {
+		phoneStr = append(phoneStr, address.Phone)
+		encrypted = append(encrypted, &address.EncryptedPhone)
+	}
 
-	if params.QueryParams.Address.AlternatePhone != "" {
-		phoneStr = append(phoneStr, params.QueryParams.Address.AlternatePhone)
+	if address.AlternatePhone != "" {
+		phoneStr = append(phoneStr, address.AlternatePhone)
+		encrypted = append(encrypted, &address.EncryptedAlternatePhone)
+	}
+	// The receiver contact is encrypted like the account holder's phone
+	if address.ReceiverName != "" {
+		phoneStr = append(phoneStr, address.ReceiverName)
+		encrypted = append(encrypted, &address.EncryptedReceiverName)
+	}
+	if address.ReceiverPhone != "" {
+		phoneStr = append(phoneStr, address.ReceiverPhone)
+		encrypted = append(encrypted, &address.EncryptedReceiverPhone)
+	}
+	if len(phoneStr) == 0 {
+		return io, nil
 	}
 
 	debugInfo := new(Debug)
-	res, err := encryptServiceObj.EncryptData(phoneStr, debugInfo)
+	res, err := encryptServiceObj.EncryptData(phoneStr, params.withContext(io.Context()), debugInfo)
 	if err != nil {
 		logger.Error("PhoneEncryption: Data Encryption Error", err)
 	}
@@ -60,16 +82,11 @@ func (a DataEncryptor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData,
 		return io, &constants.AppError{Code: constants.ResourceErrorCode, Message: "DataEncryptor: Error while parsing Encryption Service Response"}
 	}
 
-	var enPh, enAltPh string
 	for i, v := range data {
-		if i == 0 {
-			enPh = v
-		} else {
-			enAltPh = v
+		if i < len(encrypted) {
+			*encrypted[i] = v
 		}
 	}
-	params.QueryParams.Address.EncryptedPhone = enPh
-	params.QueryParams.Address.EncryptedAlternatePhone = enAltPh
 
 	return io, nil
 }
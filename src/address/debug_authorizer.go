@@ -0,0 +1,19 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"crypto/subtle"
+
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+)
+
+//isDebugAuthorized tells if the request sent the configured debug token, the debug data is returned only then
+func isDebugAuthorized(req *utilHttp.Request) bool {
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil || appConfig.Debug == nil || appConfig.Debug.Token == "" {
+		return false
+	}
+	token := req.GetHeaderParameter(appconstant.DEBUG_TOKEN)
+	return subtle.ConstantTimeCompare([]byte(appConfig.Debug.Token), []byte(token)) == 1
+}
@@ -45,13 +45,23 @@ func (a DeleteAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkF
 	}
 
 	debugInfo := new(Debug)
-	_, err := DeleteAddress(params, debugInfo)
+	addressResult, err := DeleteAddress(params, debugInfo)
 	addDebugContents(io, debugInfo)
+	if err == errAddressChanged {
+		return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: err.Error()}
+	}
+	if err == errAddressNotFound || err == errInvalidSuccessor {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
 	if err != nil {
 		logger.Error(fmt.Sprintf("There is some error occured while deleting the address %v", err), rc)
 		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
 	}
-	err = io.IOData.Set(appconstant.IO_ADDRESS_RESULT, nil)
+	// A delete with reassign returns the defaults of the customer after the delete
+	if !params.QueryParams.Reassign {
+		addressResult = nil
+	}
+	err = io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
 	if err != nil {
 		logger.Error(fmt.Sprintf("error in setting add address result to workflow data- %v", err), rc)
 		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
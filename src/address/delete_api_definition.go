@@ -2,10 +2,8 @@ package address
 
 import (
 	"common/appconstant"
-	"fmt"
 
 	"github.com/jabong/florest-core/src/common/constants"
-	"github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
 	"github.com/jabong/florest-core/src/core/common/orchestrator"
 	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
@@ -26,43 +24,7 @@ func (a *DeleteAddressAPI) GetVersion() versionmanager.Version {
 }
 
 func (a *DeleteAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
-	logger.Info("Delete Address Pipeline Creation begin")
-
-	deleteAddressOrchestrator := new(orchestrator.Orchestrator)
-	deleteAddressWorkflow := new(orchestrator.WorkFlowDefinition)
-	deleteAddressWorkflow.Create()
-
-	//Creation of the nodes in the search workflow definition
-	queryTermEnhancer := new(QueryTermEnhancer)
-	queryTermEnhancer.SetID("1")
-	err := deleteAddressWorkflow.AddExecutionNode(queryTermEnhancer)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	deleteAddressExecutor := new(DeleteAddressExecutor)
-	deleteAddressExecutor.SetID("2")
-	err = deleteAddressWorkflow.AddExecutionNode(deleteAddressExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	//Add the connections between the nodes
-	err = deleteAddressWorkflow.AddConnection(queryTermEnhancer, deleteAddressExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	//Set start node for the suggest workflow
-	deleteAddressWorkflow.SetStartNode(queryTermEnhancer)
-
-	//Assign the workflow definition to the Orchestrator
-	deleteAddressOrchestrator.Create(deleteAddressWorkflow)
-
-	logger.Info(deleteAddressOrchestrator.String())
-	logger.Info("Delete Address Pipeline Created")
-
-	return *deleteAddressOrchestrator
+	return getOrchestrator("DeleteAddress")
 }
 
 func (a *DeleteAddressAPI) GetHealthCheck() healthcheck.HCInterface {
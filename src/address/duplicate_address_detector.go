@@ -0,0 +1,67 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//DuplicateAddressDetector decides if a new address duplicates an existing address of the user. In return
+//mode the existing address is returned instead of creating a new one, in flag mode the new address is
+//created and flagged as a duplicate
+type DuplicateAddressDetector struct {
+	id string
+}
+
+func (n *DuplicateAddressDetector) SetID(id string) {
+	n.id = id
+}
+
+func (n DuplicateAddressDetector) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n DuplicateAddressDetector) Name() string {
+	return "DuplicateAddressDetector"
+}
+
+func (n DuplicateAddressDetector) GetDecision(io workflow.WorkFlowData) (bool, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("DuplicateAddressDetector")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"DuplicateAddressDetector_decision"})
+	}()
+
+	mode := getDuplicateDetectionMode()
+	if mode == appconstant.DUPLICATE_MODE_OFF {
+		return false, nil
+	}
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Duplicate Address Detector", "Duplicate Address Detector-GetDecision")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("DuplicateAddressDetector. invalid type of params")
+		return false, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	debugInfo := new(Debug)
+	addressList, order, err := getUserAddresses(params, debugInfo)
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		// Do not fail the request if the existing addresses could not be fetched
+		logger.Error(fmt.Sprintf("DuplicateAddressDetector: could not get addresses of the user - %v", err), rc)
+		return false, nil
+	}
+	duplicate := findDuplicateAddress(params.QueryParams.Address, addressList, order)
+	if duplicate == nil {
+		return false, nil
+	}
+	logger.Info(fmt.Sprintf("DuplicateAddressDetector: new address duplicates address %s, mode %s", duplicate.Id, mode), rc)
+	io.IOData.Set(appconstant.IO_DUPLICATE_OF, duplicate)
+	return mode == appconstant.DUPLICATE_MODE_RETURN, nil
+}
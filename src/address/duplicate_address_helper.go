@@ -0,0 +1,52 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"strings"
+)
+
+//getDuplicateDetectionMode returns the configured duplicate detection mode, off if not configured
+func getDuplicateDetectionMode() string {
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil || appConfig.DuplicateDetection == nil {
+		return appconstant.DUPLICATE_MODE_OFF
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(appConfig.DuplicateDetection.Mode)); mode {
+	case appconstant.DUPLICATE_MODE_RETURN, appconstant.DUPLICATE_MODE_FLAG:
+		return mode
+	}
+	return appconstant.DUPLICATE_MODE_OFF
+}
+
+//getUserAddresses gets all the addresses of the user, from cache if available else from the db
+func getUserAddresses(params *RequestParams, debug *Debug) (map[string]*AddressResponse, []string, error) {
+	addressList, order, err := getAddressListFromCache(params.RequestContext.UserID, params, debug)
+	if err == nil && len(addressList) > 0 {
+		return addressList, order, nil
+	}
+	return getAddressList(params, "", debug)
+}
+
+//findDuplicateAddress returns the first address in order which has the same postcode as address and the
+//same address lines once normalized, nil if there is none
+func findDuplicateAddress(address AddressRequest, addressList map[string]*AddressResponse, order []string) *AddressResponse {
+	postCode := strings.TrimSpace(address.PostCode)
+	key := normalizeAddress(address.Address1 + " " + address.Address2)
+	if key == "" {
+		return nil
+	}
+	for _, id := range order {
+		existing, ok := addressList[id]
+		if !ok || existing == nil {
+			continue
+		}
+		if strings.TrimSpace(existing.PostCode) != postCode {
+			continue
+		}
+		if normalizeAddress(existing.Address1+" "+existing.Address2) == key {
+			return existing
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//DuplicateAddressResponder returns the existing address matched by the DuplicateAddressDetector
+type DuplicateAddressResponder struct {
+	id string
+}
+
+func (n *DuplicateAddressResponder) SetID(id string) {
+	n.id = id
+}
+
+func (n DuplicateAddressResponder) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n DuplicateAddressResponder) Name() string {
+	return "DuplicateAddressResponder"
+}
+
+func (n DuplicateAddressResponder) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Duplicate Address Responder", "Duplicate Address Responder-Execute")
+	d, _ := io.IOData.Get(appconstant.IO_DUPLICATE_OF)
+	duplicate, ok := d.(*AddressResponse)
+	if !ok || duplicate == nil {
+		logger.Error("DuplicateAddressResponder. invalid type of duplicate address", rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "invalid type of duplicate address"}
+	}
+	addressResult := &AddressResult{
+		AddressList: map[string]*AddressResponse{duplicate.Id: duplicate},
+		DuplicateOf: duplicate.Id,
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting duplicate address result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
@@ -28,13 +28,13 @@ func InitEncryptionService(host string, timeout string) (ret *EncryptionService,
 }
 
 //EncryptData encrypt a string using the encryption service
-func (obj *EncryptionService) EncryptData(data []string, debugInfo *Debug) (body []byte, err error) {
+func (obj *EncryptionService) EncryptData(data []string, params *RequestParams, debugInfo *Debug) (body []byte, err error) {
 	reqURL := obj.Host + appconstant.ENCRYPT_ENDPOINT
 	reqURL = urlEncode(reqURL, data)
 	Timeout, err := strconv.Atoi(obj.RequestTimeOut)
 
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "EncryptionUrl", Value: reqURL})
-	response, err := utilhttp.Get(reqURL, nil, time.Duration(Timeout)*time.Millisecond)
+	response, err := utilhttp.GetWithContext(params.Context(), reqURL, getOutboundHeaders(params), time.Duration(Timeout)*time.Millisecond)
 	body = response.Body
 	if err != nil {
 		logger.Debug(fmt.Sprintf("Encryption Err. InputData: %s, Service Response %s, Error: %s", data, body, err))
@@ -43,15 +43,32 @@ func (obj *EncryptionService) EncryptData(data []string, debugInfo *Debug) (body
 }
 
 //DecryptData decrypt a string using the decryption service
-func (obj *EncryptionService) DecryptData(data []string, debugInfo *Debug) (body []byte, err error) {
+func (obj *EncryptionService) DecryptData(data []string, params *RequestParams, debugInfo *Debug) (body []byte, err error) {
 	reqURL := obj.Host + appconstant.DECRYPT_ENDPOINT
 	reqURL = urlEncode(reqURL, data)
 	Timeout, err := strconv.Atoi(obj.RequestTimeOut)
 	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "DecryptionUrl", Value: reqURL})
-	response, err := utilhttp.Get(reqURL, nil, time.Duration(Timeout)*time.Millisecond)
+	response, err := utilhttp.GetWithContext(params.Context(), reqURL, getOutboundHeaders(params), time.Duration(Timeout)*time.Millisecond)
 	body = response.Body
 	if err != nil {
 		logger.Debug(fmt.Sprintf("Decryption Err. InputData: %s, Service Response %s, Error: %s", data, body, err))
 	}
 	return body, err
 }
+
+//getOutboundHeaders gets the headers passing the request and transaction id of the request on to the
+//encryption service, so that its logs can be matched with ours. None without a request
+func getOutboundHeaders(params *RequestParams) map[string]string {
+	if params == nil {
+		return nil
+	}
+	headers := make(map[string]string, 2)
+	rc := params.RequestContext
+	if name := utilhttp.CustomHeaderMap[utilhttp.RequestID]; name != "" && rc.RequestID != "" {
+		headers[name] = rc.RequestID
+	}
+	if name := utilhttp.CustomHeaderMap[utilhttp.TransactionID]; name != "" && rc.TransactionID != "" {
+		headers[name] = rc.TransactionID
+	}
+	return headers
+}
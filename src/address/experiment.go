@@ -0,0 +1,80 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"fmt"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/monitor"
+)
+
+//getExperiments gets the configured experiments, none when the config can not be read
+func getExperiments() []*appconfig.ExperimentConfig {
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil {
+		return nil
+	}
+	return appConfig.Experiments
+}
+
+//getExperimentVariants gets the variant of every experiment the buckets of a request put it in.
+//A bucket naming a variant that is not configured leaves the request out of the experiment
+func getExperimentVariants(buckets map[string]string) map[string]string {
+	variants := make(map[string]string)
+	for _, experiment := range getExperiments() {
+		variant, found := buckets[experiment.Name]
+		if _, ok := experiment.Variants[variant]; found && ok {
+			variants[experiment.Name] = variant
+		}
+	}
+	return variants
+}
+
+//recordExposures logs the experiments a request is exposed to and counts them, tagged with the experiment and variant
+func recordExposures(variants map[string]string, rc interface{}) {
+	for experiment, variant := range variants {
+		logger.Info(fmt.Sprintf("ExperimentExposure|%s|%s", experiment, variant), rc)
+		tags := []string{"experiment:" + experiment, "variant:" + variant}
+		if err := monitor.GetInstance().Count(appconstant.EXPERIMENT_EXPOSURE_METRIC, 1, tags, 1); err != nil {
+			logger.Error(fmt.Sprintf("Monitoring Error %v", err), rc)
+		}
+	}
+}
+
+//getExperimentParam gets a parameter set by a variant the request is in, the default when none sets it.
+//The experiments are looked at in the order they are configured
+func getExperimentParam(params *RequestParams, key string, defaultValue string) string {
+	for _, experiment := range getExperiments() {
+		variant, found := params.Experiments[experiment.Name]
+		if !found || experiment.Variants[variant] == nil {
+			continue
+		}
+		if value, ok := experiment.Variants[variant].Params[key]; ok {
+			return value
+		}
+	}
+	return defaultValue
+}
+
+//validateExperiments checks that the workflows the variants replace and run are defined
+func validateExperiments(workflows map[string]bool) error {
+	for _, experiment := range getExperiments() {
+		if experiment.Name == "" || strings.ContainsAny(experiment.Name, ",:") {
+			return fmt.Errorf("Invalid experiment name %q", experiment.Name)
+		}
+		for variant, v := range experiment.Variants {
+			if v == nil {
+				continue
+			}
+			for replaced, replacement := range v.Workflows {
+				if !workflows[replaced] || !workflows[replacement] {
+					return fmt.Errorf("Variant %s of experiment %s replaces workflow %s by %s, both should be defined",
+						variant, experiment.Name, replaced, replacement)
+				}
+			}
+		}
+	}
+	return nil
+}
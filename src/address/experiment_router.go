@@ -0,0 +1,84 @@
+package address
+
+import (
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//ExperimentRouter runs the workflow of the experiment variant a request is in, the workflow
+//it replaces otherwise. It is the only node of a workflow replaced by an experiment
+type ExperimentRouter struct {
+	id       string
+	routes   []experimentRoute
+	fallback workflow.Orchestrator
+}
+
+//experimentRoute is the workflow run for the requests in a variant of an experiment
+type experimentRoute struct {
+	experiment   string
+	variant      string
+	orchestrator workflow.Orchestrator
+}
+
+func (n *ExperimentRouter) SetID(id string) {
+	n.id = id
+}
+
+func (n ExperimentRouter) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n ExperimentRouter) Name() string {
+	return "ExperimentRouter"
+}
+
+func (n ExperimentRouter) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	buckets, _ := io.ExecContext.GetBuckets()
+	o := n.fallback
+	for _, route := range n.routes {
+		if variant, found := buckets[route.experiment]; found && variant == route.variant {
+			logger.Info(fmt.Sprintf("ExperimentRouter: running the workflow of variant %s of experiment %s", route.variant, route.experiment), rc)
+			io.ExecContext.SetDebugMsg("Experiment Router", route.experiment+":"+route.variant)
+			o = route.orchestrator
+			break
+		}
+	}
+	return *o.Start(&io), nil
+}
+
+//getExperimentOrchestrator wraps the orchestrator of a workflow in an experiment router when
+//a variant replaces the workflow. A request in several of the experiments runs the variant of the first configured one
+func getExperimentOrchestrator(name string, o workflow.Orchestrator) workflow.Orchestrator {
+	router := &ExperimentRouter{fallback: o}
+	for _, experiment := range getExperiments() {
+		for variant, v := range experiment.Variants {
+			if v == nil || v.Workflows[name] == "" {
+				continue
+			}
+			router.routes = append(router.routes, experimentRoute{experiment: experiment.Name, variant: variant,
+				orchestrator: createOrchestrator(v.Workflows[name])})
+		}
+	}
+	if len(router.routes) == 0 {
+		return o
+	}
+	router.SetID("1")
+	definition := new(workflow.WorkFlowDefinition)
+	definition.Create()
+	if err := definition.AddExecutionNode(router); err != nil {
+		panic(fmt.Sprintf("Experiment router of workflow %s - %v", name, err))
+	}
+	if err := definition.SetStartNode(router); err != nil {
+		panic(fmt.Sprintf("Experiment router of workflow %s - %v", name, err))
+	}
+	routed := new(workflow.Orchestrator)
+	if err := routed.Create(definition); err != nil {
+		panic(fmt.Sprintf("Experiment router of workflow %s - %v", name, err))
+	}
+	logger.Info(fmt.Sprintf("Workflow %s is routed by experiment to %d variants", name, len(router.routes)))
+	return *routed
+}
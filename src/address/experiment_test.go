@@ -0,0 +1,75 @@
+package address
+
+import (
+	"common/appconfig"
+
+	"github.com/jabong/florest-core/src/common/config"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Experiments", func() {
+	experiments := []*appconfig.ExperimentConfig{
+		{Name: "listflow", Variants: map[string]*appconfig.ExperimentVariant{
+			"control":  nil,
+			"parallel": {Workflows: map[string]string{"ListAddress": "ListAddressParallel"}},
+		}},
+		{Name: "dedupe", Variants: map[string]*appconfig.ExperimentVariant{
+			"strict": {Params: map[string]string{"DuplicateMode": "strict"}},
+			"loose":  {Params: map[string]string{"DuplicateMode": "loose", "MaxAddresses": "20"}},
+		}},
+	}
+	var previous interface{}
+
+	gk.BeforeEach(func() {
+		previous = config.GlobalAppConfig.ApplicationConfig
+		config.GlobalAppConfig.ApplicationConfig = &appconfig.AddressServiceConfig{Experiments: experiments}
+	})
+
+	gk.AfterEach(func() {
+		config.GlobalAppConfig.ApplicationConfig = previous
+	})
+
+	gk.It("should put a request in the variants its buckets select", func() {
+		tests := []struct {
+			name     string
+			buckets  map[string]string
+			variants map[string]string
+		}{
+			{"no buckets", nil, map[string]string{}},
+			{"one experiment", map[string]string{"listflow": "parallel"},
+				map[string]string{"listflow": "parallel"}},
+			{"variant without changes", map[string]string{"listflow": "control"},
+				map[string]string{"listflow": "control"}},
+			{"every experiment", map[string]string{"listflow": "control", "dedupe": "loose"},
+				map[string]string{"listflow": "control", "dedupe": "loose"}},
+			{"unknown variant", map[string]string{"listflow": "serial", "dedupe": "strict"},
+				map[string]string{"dedupe": "strict"}},
+			{"unknown experiment", map[string]string{"checkout": "new"}, map[string]string{}},
+		}
+		for _, test := range tests {
+			gm.Expect(getExperimentVariants(test.buckets)).To(gm.Equal(test.variants), test.name)
+		}
+	})
+
+	gk.It("should get the parameters of the variants a request is in", func() {
+		tests := []struct {
+			name     string
+			variants map[string]string
+			key      string
+			value    string
+		}{
+			{"in no experiment", nil, "DuplicateMode", "default"},
+			{"set by the variant", map[string]string{"dedupe": "strict"}, "DuplicateMode", "strict"},
+			{"set by another variant", map[string]string{"dedupe": "loose"}, "MaxAddresses", "20"},
+			{"not set by the variant", map[string]string{"dedupe": "strict"}, "MaxAddresses", "default"},
+			{"variant without parameters", map[string]string{"listflow": "control", "dedupe": "loose"},
+				"DuplicateMode", "loose"},
+			{"unknown variant", map[string]string{"dedupe": "off"}, "DuplicateMode", "default"},
+		}
+		for _, test := range tests {
+			params := &RequestParams{Experiments: test.variants}
+			gm.Expect(getExperimentParam(params, test.key, "default")).To(gm.Equal(test.value), test.name)
+		}
+	})
+})
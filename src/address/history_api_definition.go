@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AddressHistoryAPI struct {
+}
+
+func (a *AddressHistoryAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "GET",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/history",
+	}
+}
+
+func (a *AddressHistoryAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AddressHistory")
+}
+
+func (a *AddressHistoryAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AddressHistoryAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AddressHistoryAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
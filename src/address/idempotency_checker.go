@@ -0,0 +1,91 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//IdempotencyChecker decides if a create request carrying an Idempotency-Key is a retry of a completed
+//request, in which case the stored response is replayed instead of creating the address again
+type IdempotencyChecker struct {
+	id string
+}
+
+func (n *IdempotencyChecker) SetID(id string) {
+	n.id = id
+}
+
+func (n IdempotencyChecker) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n IdempotencyChecker) Name() string {
+	return "IdempotencyChecker"
+}
+
+func (n IdempotencyChecker) GetDecision(io workflow.WorkFlowData) (bool, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("IdempotencyChecker")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"IdempotencyChecker_decision"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Idempotency Checker", "Idempotency Checker-GetDecision")
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+	key := strings.TrimSpace(appHTTPReq.GetHeaderParameter(appconstant.IDEMPOTENCY_KEY))
+	if key == "" {
+		return false, nil
+	}
+	if len(key) > 255 {
+		return false, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "Idempotency-Key must be at most 255 characters"}
+	}
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("IdempotencyChecker. invalid type of params")
+		return false, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+
+	// The body is read again by the AddressValidator
+	body, err := appHTTPReq.GetBodyParameter()
+	if err != nil {
+		return false, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	appHTTPReq.OriginalRequest.Body = ioutil.NopCloser(strings.NewReader(body))
+
+	record := &IdempotencyRecord{
+		CacheKey: GetIdempotencyCacheKey(params.RequestContext.UserID, key),
+		BodyHash: hashRequestBody(body),
+		Status:   appconstant.IDEMPOTENCY_STATUS_PENDING,
+	}
+	existing, err := claimIdempotencyKey(record)
+	if err != nil {
+		// Do not fail the request if the cache is unavailable
+		logger.Error(fmt.Sprintf("IdempotencyChecker: could not claim Idempotency-Key %s - %v", key, err), rc)
+		return false, nil
+	}
+	if existing == nil {
+		io.IOData.Set(appconstant.IO_IDEMPOTENCY_RECORD, record)
+		return false, nil
+	}
+	if existing.BodyHash != record.BodyHash {
+		return false, &constants.AppError{Code: appconstant.IdempotencyConflictErrorCode, Message: "Idempotency-Key has already been used with a different request body"}
+	}
+	if existing.Status != appconstant.IDEMPOTENCY_STATUS_COMPLETED {
+		return false, &constants.AppError{Code: appconstant.IdempotencyConflictErrorCode, Message: "A request with this Idempotency-Key is still in progress"}
+	}
+	logger.Info(fmt.Sprintf("IdempotencyChecker: replaying response of Idempotency-Key %s", key), rc)
+	io.IOData.Set(appconstant.IO_IDEMPOTENCY_RECORD, existing)
+	return true, nil
+}
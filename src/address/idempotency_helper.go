@@ -0,0 +1,118 @@
+package address
+
+import (
+	"common/appconstant"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/cache"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//IdempotencyRecord is stored in cache against the Idempotency-Key of a user. It is pending while
+//the first request is processed and holds the response once that request has completed
+type IdempotencyRecord struct {
+	CacheKey string          `json:"-"`
+	BodyHash string          `json:"BodyHash"`
+	Status   string          `json:"Status"`
+	Result   json.RawMessage `json:"Result,omitempty"`
+}
+
+//GetIdempotencyCacheKey return the cache key of the Idempotency-Key of a user
+func GetIdempotencyCacheKey(userID string, key string) string {
+	return fmt.Sprintf(appconstant.IDEMPOTENCY_CACHE_KEY, userID, key)
+}
+
+//hashRequestBody returns the hex encoded SHA-256 of the request body
+func hashRequestBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+//claimIdempotencyKey stores record as pending if its key is not in use. If the key is already in use
+//the stored record is returned instead
+func claimIdempotencyKey(record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	p := profiler.NewProfiler()
+	p.StartProfile("IdempotencyHelper#claimIdempotencyKey")
+	defer func() {
+		p.EndProfileWithMetric([]string{"IdempotencyHelper#claimIdempotencyKey"})
+	}()
+
+	cacheObj, err := cache.Get(cache.Redis)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Redis Config Error - %v", err))
+		return nil, err
+	}
+	str, _ := json.Marshal(record)
+	item := cache.Item{Key: record.CacheKey, Value: string(str)}
+	claimed, err := cacheObj.SetIfNotExists(item, false, false, appconstant.IDEMPOTENCY_LOCK_TTL)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+	result, err := cacheObj.Get(record.CacheKey, false, false)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := result.Value.(string)
+	existing := new(IdempotencyRecord)
+	if err = json.Unmarshal([]byte(data), existing); err != nil {
+		return nil, err
+	}
+	existing.CacheKey = record.CacheKey
+	return existing, nil
+}
+
+//saveIdempotencyResult marks record as completed with the response of the request
+func saveIdempotencyResult(record *IdempotencyRecord, result interface{}) error {
+	p := profiler.NewProfiler()
+	p.StartProfile("IdempotencyHelper#saveIdempotencyResult")
+	defer func() {
+		p.EndProfileWithMetric([]string{"IdempotencyHelper#saveIdempotencyResult"})
+	}()
+
+	cacheObj, err := cache.Get(cache.Redis)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Redis Config Error - %v", err))
+		return err
+	}
+	record.Result, err = json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	record.Status = appconstant.IDEMPOTENCY_STATUS_COMPLETED
+	str, _ := json.Marshal(record)
+	item := cache.Item{Key: record.CacheKey, Value: string(str)}
+	return cacheObj.SetWithTimeout(item, false, false, appconstant.IDEMPOTENCY_KEY_TTL)
+}
+
+//releaseIdempotencyKey is the failure hook of the create workflow, it deletes the pending record claimed by the
+//request so that a retry with the same Idempotency-Key is processed instead of refused as in progress
+func releaseIdempotencyKey(wfData *workflow.WorkFlowData, err error) {
+	if wfData == nil || err == nil {
+		return
+	}
+	r, _ := wfData.IOData.Get(appconstant.IO_IDEMPOTENCY_RECORD)
+	record, ok := r.(*IdempotencyRecord)
+	if !ok || record == nil || record.Status != appconstant.IDEMPOTENCY_STATUS_PENDING {
+		return
+	}
+	if res, _ := wfData.IOData.Get(appconstant.IO_ADDRESS_RESULT); res != nil {
+		//The address was created, a retry must not create it again, the record expires after IDEMPOTENCY_LOCK_TTL
+		return
+	}
+	cacheObj, cerr := cache.Get(cache.Redis)
+	if cerr == nil {
+		cerr = cacheObj.Delete(record.CacheKey)
+	}
+	if cerr != nil {
+		// The pending record expires after IDEMPOTENCY_LOCK_TTL
+		logger.Error(fmt.Sprintf("IdempotencyHelper: could not release %s - %v", record.CacheKey, cerr))
+	}
+}
@@ -0,0 +1,44 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//IdempotencyRecorder stores the response of a create request against its Idempotency-Key
+type IdempotencyRecorder struct {
+	id string
+}
+
+func (n *IdempotencyRecorder) SetID(id string) {
+	n.id = id
+}
+
+func (n IdempotencyRecorder) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n IdempotencyRecorder) Name() string {
+	return "IdempotencyRecorder"
+}
+
+func (n IdempotencyRecorder) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Idempotency Recorder", "Idempotency Recorder-Execute")
+	r, _ := io.IOData.Get(appconstant.IO_IDEMPOTENCY_RECORD)
+	record, ok := r.(*IdempotencyRecord)
+	if !ok || record == nil {
+		// No Idempotency-Key in the request
+		return io, nil
+	}
+	result, _ := io.IOData.Get(appconstant.IO_ADDRESS_RESULT)
+	if err := saveIdempotencyResult(record, result); err != nil {
+		// The address is already created, a retry after the pending record expires creates it again
+		logger.Error(fmt.Sprintf("IdempotencyRecorder: could not save the response of %s - %v", record.CacheKey, err), rc)
+	}
+	return io, nil
+}
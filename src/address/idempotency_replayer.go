@@ -0,0 +1,44 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//IdempotencyReplayer returns the stored response of a completed request with the same Idempotency-Key
+type IdempotencyReplayer struct {
+	id string
+}
+
+func (n *IdempotencyReplayer) SetID(id string) {
+	n.id = id
+}
+
+func (n IdempotencyReplayer) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n IdempotencyReplayer) Name() string {
+	return "IdempotencyReplayer"
+}
+
+func (n IdempotencyReplayer) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Idempotency Replayer", "Idempotency Replayer-Execute")
+	r, _ := io.IOData.Get(appconstant.IO_IDEMPOTENCY_RECORD)
+	record, ok := r.(*IdempotencyRecord)
+	if !ok || record == nil {
+		logger.Error("IdempotencyReplayer. invalid type of idempotency record", rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "invalid type of idempotency record"}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, record.Result)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting replayed address result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
@@ -0,0 +1,166 @@
+package address
+
+import (
+	"common/appconstant"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	fconstants "github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/components/cache"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/service"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//memoryCache is a cache kept in memory, used as the Redis cache when none is configured
+type memoryCache struct {
+	mutex sync.Mutex
+	items map[string]interface{}
+}
+
+func (c *memoryCache) Init(conf *cache.Config) error {
+	c.items = make(map[string]interface{})
+	return nil
+}
+
+func (c *memoryCache) Get(key string, serialize bool, compress bool) (*cache.Item, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	v, ok := c.items[key]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", key)
+	}
+	return &cache.Item{Key: key, Value: v}, nil
+}
+
+func (c *memoryCache) Set(item cache.Item, serialize bool, compress bool) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.items[item.Key] = item.Value
+	return nil
+}
+
+func (c *memoryCache) SetWithTimeout(item cache.Item, serialize bool, compress bool, ttl int32) error {
+	return c.Set(item, serialize, compress)
+}
+
+func (c *memoryCache) SetIfNotExists(item cache.Item, serialize bool, compress bool, ttl int32) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.items[item.Key]; ok {
+		return false, nil
+	}
+	c.items[item.Key] = item.Value
+	return true, nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.items, key)
+	return nil
+}
+
+func (c *memoryCache) DeleteBatch(keys []string) error {
+	for _, key := range keys {
+		c.Delete(key)
+	}
+	return nil
+}
+
+func (c *memoryCache) GetBatch(keys []string, serialize bool, compress bool) (map[string]*cache.Item, error) {
+	items := make(map[string]*cache.Item)
+	for _, key := range keys {
+		if item, err := c.Get(key, serialize, compress); err == nil {
+			items[key] = item
+		}
+	}
+	return items, nil
+}
+
+//getCreateWorkflowData returns the workflow data of a create request sending body with the Idempotency-Key key,
+//as the QueryTermEnhancer leaves it
+func getCreateWorkflowData(key string, body string) workflow.WorkFlowData {
+	request, _ := http.NewRequest("POST", "/AddressService/v1/address/", strings.NewReader(body))
+	request.Header.Set(appconstant.IDEMPOTENCY_KEY, key)
+	data, err := service.GetData(request)
+	gm.Expect(err).To(gm.BeNil())
+	params := &RequestParams{}
+	params.RequestContext.UserID = userID
+	data.IOData.Set(appconstant.IO_REQUEST_PARAMS, params)
+	return *data
+}
+
+//isTestCacheAvailable tells if the Redis cache can be used, a cache in memory is used if none is configured
+func isTestCacheAvailable() bool {
+	if _, err := cache.Get(cache.Redis); err != nil {
+		cache.Set(cache.Redis, nil, new(memoryCache))
+	}
+	cacheObj, err := cache.Get(cache.Redis)
+	if err != nil {
+		return false
+	}
+	_, err = cacheObj.SetIfNotExists(cache.Item{Key: "idempotency_test", Value: "1"}, false, false, 1)
+	return err == nil
+}
+
+var _ = gk.Describe("Idempotency-Key", func() {
+	if !isTestCacheAvailable() {
+		gk.PIt("needs the Redis of the test config")
+		return
+	}
+	checker := IdempotencyChecker{}
+	validator := AddressValidator{}
+
+	gk.Context("when the create fails after the key was claimed", func() {
+		gk.It("should process the retry with the same key", func() {
+			key := fmt.Sprintf("retry-%d", time.Now().UnixNano())
+			body := `{"firstName":`
+
+			io := getCreateWorkflowData(key, body)
+			replay, err := checker.GetDecision(io)
+			gm.Expect(replay).To(gm.BeFalse())
+			gm.Expect(err).To(gm.BeNil())
+
+			// The key is in use while the create is in progress
+			_, err = checker.GetDecision(getCreateWorkflowData(key, body))
+			appErr, ok := err.(*fconstants.AppError)
+			gm.Expect(ok).To(gm.BeTrue())
+			gm.Expect(appErr.Code).To(gm.Equal(appconstant.IdempotencyConflictErrorCode))
+
+			_, err = validator.Execute(io)
+			gm.Expect(err).NotTo(gm.BeNil())
+			releaseIdempotencyKey(&io, err)
+
+			retry := getCreateWorkflowData(key, body)
+			replay, err = checker.GetDecision(retry)
+			gm.Expect(replay).To(gm.BeFalse())
+			gm.Expect(err).To(gm.BeNil())
+			record, _ := retry.IOData.Get(appconstant.IO_IDEMPOTENCY_RECORD)
+			gm.Expect(record).NotTo(gm.BeNil())
+		})
+	})
+
+	gk.Context("when the create fails after the address was written", func() {
+		gk.It("should keep the key in use", func() {
+			key := fmt.Sprintf("written-%d", time.Now().UnixNano())
+			body := `{"firstName":"Asha"}`
+
+			io := getCreateWorkflowData(key, body)
+			_, err := checker.GetDecision(io)
+			gm.Expect(err).To(gm.BeNil())
+			io.IOData.Set(appconstant.IO_ADDRESS_RESULT, &AddressResult{})
+			releaseIdempotencyKey(&io, errors.New("timed out"))
+
+			_, err = checker.GetDecision(getCreateWorkflowData(key, body))
+			appErr, ok := err.(*fconstants.AppError)
+			gm.Expect(ok).To(gm.BeTrue())
+			gm.Expect(appErr.Code).To(gm.Equal(appconstant.IdempotencyConflictErrorCode))
+		})
+	})
+})
@@ -2,10 +2,8 @@ package address
 
 import (
 	"common/appconstant"
-	"fmt"
 
 	"github.com/jabong/florest-core/src/common/constants"
-	"github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
 	"github.com/jabong/florest-core/src/core/common/orchestrator"
 	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
@@ -26,59 +24,7 @@ func (a *ListAddressAPI) GetVersion() versionmanager.Version {
 }
 
 func (a *ListAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
-	logger.Info("Address Pipeline Creation begin")
-
-	addressOrchestrator := new(orchestrator.Orchestrator)
-	listAddressWorkflow := new(orchestrator.WorkFlowDefinition)
-	listAddressWorkflow.Create()
-
-	//Creation of the nodes in the workflow definition
-
-	queryTermEnhancer := new(QueryTermEnhancer)
-	queryTermEnhancer.SetID("1")
-	err := listAddressWorkflow.AddExecutionNode(queryTermEnhancer)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-
-	}
-
-	queryTermValidator := new(QueryTermValidator)
-	queryTermValidator.SetID("2")
-	err = listAddressWorkflow.AddExecutionNode(queryTermValidator)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-
-	}
-	listAddressExecutor := new(ListAddressExecutor)
-	listAddressExecutor.SetID("3")
-	err = listAddressWorkflow.AddExecutionNode(listAddressExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-
-	}
-
-	//Add the connection between the nodes
-	err = listAddressWorkflow.AddConnection(queryTermEnhancer, queryTermValidator)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-
-	}
-
-	err = listAddressWorkflow.AddConnection(queryTermValidator, listAddressExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-
-	}
-
-	//Set start node for the search workflow
-	listAddressWorkflow.SetStartNode(queryTermEnhancer)
-
-	//Assign the workflow definition to the Orchestrator
-	addressOrchestrator.Create(listAddressWorkflow)
-
-	logger.Info(addressOrchestrator.String())
-	logger.Info("Address Pipeline Created")
-	return *addressOrchestrator
+	return getOrchestrator("ListAddress")
 }
 
 func (a *ListAddressAPI) GetHealthCheck() healthcheck.HCInterface {
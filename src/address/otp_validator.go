@@ -0,0 +1,68 @@
+package address
+
+import (
+	"common/appconstant"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//OtpValidator validates the code in the body of a phone verification confirmation
+type OtpValidator struct {
+	id string
+}
+
+func (n *OtpValidator) SetID(id string) {
+	n.id = id
+}
+
+func (n OtpValidator) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n OtpValidator) Name() string {
+	return "OtpValidator"
+}
+
+func (n OtpValidator) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("OtpValidator")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"OtpValidator_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Otp Validator", "Otp Validator-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("OtpValidator. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+	bodyParam, err := appHTTPReq.GetBodyParameter()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid Body Param: %v", err), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	var body map[string]interface{}
+	if err = json.Unmarshal([]byte(bodyParam), &body); err != nil {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	code, ok := body[appconstant.OTP_CODE].(string)
+	code = strings.TrimSpace(code)
+	if !ok || code == "" || strings.Trim(code, "0123456789") != "" || len(code) != getPhoneVerificationConfig().codeLength {
+		msg := fmt.Sprintf("Field name '%s' is expected to be a %d digit code", appconstant.OTP_CODE, getPhoneVerificationConfig().codeLength)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: msg}
+	}
+	params.QueryParams.Otp = code
+	return io, nil
+}
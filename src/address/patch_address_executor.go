@@ -0,0 +1,66 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//PatchAddressExecutor applies a JSON Merge Patch to an address
+type PatchAddressExecutor struct {
+	id string
+}
+
+func (n *PatchAddressExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n PatchAddressExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n PatchAddressExecutor) Name() string {
+	return "PatchAddressExecutor"
+}
+
+func (n PatchAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("PatchAddressExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"PatchAddressExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Patch Address Executor", "Patch Address Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("PatchAddressExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+
+	debugInfo := new(Debug)
+	addressResult, err := PatchAddress(params, debugInfo)
+	addDebugContents(io, debugInfo)
+	if err == errAddressNotFound {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	if err == errAddressChanged {
+		return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: err.Error()}
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("There is some error occured while patching the address %v", err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting patched address result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
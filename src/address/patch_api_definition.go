@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type PatchAddressAPI struct {
+}
+
+func (a *PatchAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "PATCH",
+		BucketID: constants.OrchestratorBucketDefaultValue, //todo - should it be a constant
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}",
+	}
+}
+
+func (a *PatchAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("PatchAddress")
+}
+
+func (a *PatchAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *PatchAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *PatchAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
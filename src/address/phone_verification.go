@@ -0,0 +1,296 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/cache"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+var (
+	errOtpResendTooSoon    = errors.New("A code was sent recently, try again later")
+	errOtpAttemptsExceeded = errors.New("Too many wrong codes, request a new code")
+	errOtpExpired          = errors.New("The code has expired, request a new code")
+	errOtpInvalid          = errors.New("The code is not valid")
+	errOtpPhoneChanged     = errors.New("The phone has changed since the code was sent, request a new code")
+	errAddressHasNoPhone   = errors.New("The address has no phone")
+)
+
+//getPhoneVerificationAppError maps the phone verification errors to the error returned to the client
+func getPhoneVerificationAppError(err error) *constants.AppError {
+	switch err {
+	case errOtpResendTooSoon, errOtpAttemptsExceeded:
+		return &constants.AppError{Code: appconstant.OtpTooManyRequestsErrorCode, Message: err.Error()}
+	case errAddressNotFound, errAddressHasNoPhone, errOtpExpired, errOtpInvalid, errOtpPhoneChanged:
+		return &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	return &constants.AppError{Code: constants.ResourceErrorCode, Message: err.Error()}
+}
+
+//OtpRecord is stored in cache while a code is valid. The phone is kept encrypted, as stored in the db,
+//so that the code only verifies the phone it was sent to
+type OtpRecord struct {
+	CodeHash       string `json:"CodeHash"`
+	EncryptedPhone string `json:"EncryptedPhone"`
+}
+
+//PhoneVerificationResult tells where the code was sent and for how long it is valid
+type PhoneVerificationResult struct {
+	Phone     string `json:"phone"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+//phoneVerificationConfig is the phone verification config with the defaults for what is not configured
+type phoneVerificationConfig struct {
+	codeLength     int
+	expirySeconds  int
+	maxAttempts    int
+	resendInterval int
+}
+
+func getPhoneVerificationConfig() phoneVerificationConfig {
+	c := phoneVerificationConfig{
+		codeLength:     appconstant.DEFAULT_OTP_LENGTH,
+		expirySeconds:  appconstant.DEFAULT_OTP_EXPIRY_SECONDS,
+		maxAttempts:    appconstant.DEFAULT_OTP_MAX_ATTEMPTS,
+		resendInterval: appconstant.DEFAULT_OTP_RESEND_SECONDS,
+	}
+	appConfig, err := appconfig.GetAddressServiceConfig()
+	if err != nil || appConfig.PhoneVerification == nil {
+		return c
+	}
+	if appConfig.PhoneVerification.CodeLength > 0 {
+		c.codeLength = appConfig.PhoneVerification.CodeLength
+	}
+	if appConfig.PhoneVerification.ExpirySeconds > 0 {
+		c.expirySeconds = appConfig.PhoneVerification.ExpirySeconds
+	}
+	if appConfig.PhoneVerification.MaxAttempts > 0 {
+		c.maxAttempts = appConfig.PhoneVerification.MaxAttempts
+	}
+	if appConfig.PhoneVerification.ResendIntervalSeconds > 0 {
+		c.resendInterval = appConfig.PhoneVerification.ResendIntervalSeconds
+	}
+	return c
+}
+
+//generateOtp returns a random numeric code of length digits
+func generateOtp(length int) (string, error) {
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
+
+//hashOtp returns the hex encoded SHA-256 of a code, bound to the address it was sent for
+func hashOtp(userID string, addressID string, code string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + addressID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+//maskPhone hides all but the last 4 digits of a phone
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return phone
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
+
+func getOtpCacheKey(userID string, addressID string) string {
+	return fmt.Sprintf(appconstant.OTP_CACHE_KEY, userID, addressID)
+}
+
+func getOtpAttemptCacheKeys(userID string, addressID string, maxAttempts int) []string {
+	keys := make([]string, maxAttempts)
+	for i := range keys {
+		keys[i] = fmt.Sprintf(appconstant.OTP_ATTEMPT_CACHE_KEY, userID, addressID, i+1)
+	}
+	return keys
+}
+
+//claimOtpAttempt takes the first of the attempts of a code not yet taken, false once they are all used up.
+//Claiming an attempt key is atomic, concurrent checks can not go past the limit
+func claimOtpAttempt(cacheObj cache.CInterface, attemptKeys []string, expirySeconds int) (bool, error) {
+	for _, key := range attemptKeys {
+		claimed, err := cacheObj.SetIfNotExists(cache.Item{Key: key, Value: "1"}, false, false, int32(expirySeconds))
+		if err != nil || claimed {
+			return claimed, err
+		}
+	}
+	return false, nil
+}
+
+//getEncryptedPhone gets the phone of an address as stored in the db, errAddressNotFound if there is no such address
+func getEncryptedPhone(params *RequestParams, debugInfo *Debug) (string, error) {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return "", err
+	}
+	sql := `SELECT IFNULL(phone, "") FROM customer_address WHERE id_customer_address = ? AND fk_customer = ? AND deleted_at IS NULL`
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "getEncryptedPhone:Sql", Value: sql + "id_customer_address: " + addressID})
+	rows, qerr := db.Query(sql, addressID, params.RequestContext.UserID)
+	if qerr != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while getting the phone of the address |%s|%s|%s", appconstant.MYSQL_ERROR, qerr.Error(), "customer_address"))
+		return "", qerr
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return "", errAddressNotFound
+	}
+	var phone string
+	if err := rows.Scan(&phone); err != nil {
+		return "", err
+	}
+	return phone, nil
+}
+
+//sendPhoneVerification sends a new code to the phone of an address. A new code replaces the previous one
+//and resets the attempts, codes can be requested once per resend interval
+func sendPhoneVerification(params *RequestParams, debugInfo *Debug) (*PhoneVerificationResult, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("PhoneVerification#sendPhoneVerification")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"PhoneVerification#sendPhoneVerification"})
+	}()
+
+	conf := getPhoneVerificationConfig()
+	userID := params.RequestContext.UserID
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	cacheObj, err := cache.GetWithContext(params.Context(), cache.Redis)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Redis Config Error - %v", err))
+		return nil, err
+	}
+
+	encryptedPhone, err := getEncryptedPhone(params, debugInfo)
+	if err != nil {
+		return nil, err
+	}
+	if encryptedPhone == "" {
+		return nil, errAddressHasNoPhone
+	}
+	decrypted, stats := decrypt([]string{encryptedPhone}, params, debugInfo)
+	countDecryptionFailures(params, stats)
+	if len(decrypted) != 1 || decrypted[0] == "" || decrypted[0] == "0" {
+		return nil, errors.New("Could not decrypt the phone of the address")
+	}
+	phone := decrypted[0]
+
+	resendKey := fmt.Sprintf(appconstant.OTP_RESEND_CACHE_KEY, userID, addressID)
+	claimed, err := cacheObj.SetIfNotExists(cache.Item{Key: resendKey, Value: "1"}, false, false, int32(conf.resendInterval))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errOtpResendTooSoon
+	}
+
+	code, err := generateOtp(conf.codeLength)
+	if err != nil {
+		return nil, err
+	}
+	otpKey := getOtpCacheKey(userID, addressID)
+	str, _ := json.Marshal(OtpRecord{CodeHash: hashOtp(userID, addressID, code), EncryptedPhone: encryptedPhone})
+	if err = cacheObj.SetWithTimeout(cache.Item{Key: otpKey, Value: string(str)}, false, false, int32(conf.expirySeconds)); err != nil {
+		return nil, err
+	}
+	if err = cacheObj.DeleteBatch(getOtpAttemptCacheKeys(userID, addressID, conf.maxAttempts)); err != nil {
+		logger.Warning(fmt.Sprintf("sendPhoneVerification: Could not reset the attempts of address %s - %v", addressID, err), params.RequestContext)
+	}
+	message := fmt.Sprintf(appconstant.OTP_MESSAGE, code, conf.expirySeconds/60)
+	if err = smsSenderObj.Send(phone, message); err != nil {
+		logger.Error(fmt.Sprintf("sendPhoneVerification: Could not send the code for address %s - %v", addressID, err), params.RequestContext)
+		cacheObj.Delete(otpKey)
+		cacheObj.Delete(resendKey)
+		return nil, err
+	}
+	return &PhoneVerificationResult{Phone: maskPhone(phone), ExpiresIn: conf.expirySeconds}, nil
+}
+
+//confirmPhoneVerification checks a code and marks the phone of the address verified. Every check takes
+//one of the attempts of the code, the code is dropped once they are used up
+func confirmPhoneVerification(params *RequestParams, debugInfo *Debug) error {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("PhoneVerification#confirmPhoneVerification")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"PhoneVerification#confirmPhoneVerification"})
+	}()
+
+	conf := getPhoneVerificationConfig()
+	userID := params.RequestContext.UserID
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	cacheObj, err := cache.GetWithContext(params.Context(), cache.Redis)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Redis Config Error - %v", err))
+		return err
+	}
+	otpKey := getOtpCacheKey(userID, addressID)
+	result, err := cacheObj.Get(otpKey, false, false)
+	if err != nil || result == nil {
+		return errOtpExpired
+	}
+	data, _ := result.Value.(string)
+	record := new(OtpRecord)
+	if err = json.Unmarshal([]byte(data), record); err != nil {
+		return errOtpExpired
+	}
+
+	attemptKeys := getOtpAttemptCacheKeys(userID, addressID, conf.maxAttempts)
+	claimed, err := claimOtpAttempt(cacheObj, attemptKeys, conf.expirySeconds)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		cacheObj.Delete(otpKey)
+		return errOtpAttemptsExceeded
+	}
+	codeHash := hashOtp(userID, addressID, params.QueryParams.Otp)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(record.CodeHash)) != 1 {
+		return errOtpInvalid
+	}
+
+	err = setPhoneVerifiedInDb(params, record.EncryptedPhone, debugInfo)
+	cacheObj.Delete(otpKey)
+	cacheObj.DeleteBatch(attemptKeys)
+	return err
+}
+
+//setPhoneVerifiedInDb marks the phone of an address verified if it is still the phone the code was sent to
+func setPhoneVerifiedInDb(params *RequestParams, encryptedPhone string, debugInfo *Debug) error {
+	db, err := sqldb.GetWithContext(params.Context(), "mysdb")
+	if err != nil {
+		return err
+	}
+	addressID := strconv.Itoa(params.QueryParams.AddressId)
+	sql := `UPDATE customer_address SET phone_verified = 1, version = version + 1 WHERE id_customer_address = ? AND fk_customer = ? AND deleted_at IS NULL AND phone = ?`
+	debugInfo.MessageStack = append(debugInfo.MessageStack, DebugInfo{Key: "setPhoneVerifiedInDb:Sql", Value: sql + "id_customer_address: " + addressID})
+	res, eerr := db.Execute(sql, addressID, params.RequestContext.UserID, encryptedPhone)
+	if eerr != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while verifying the phone of the address |%s|%s|%s", appconstant.MYSQL_ERROR, eerr.Error(), "customer_address"))
+		return eerr
+	}
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+		return errOtpPhoneChanged
+	}
+	return nil
+}
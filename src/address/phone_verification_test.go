@@ -0,0 +1,115 @@
+package address
+
+import (
+	"common/appconstant"
+	"errors"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/components/cache"
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+//failingCache is a cache whose writes fail
+type failingCache struct {
+	*memoryCache
+}
+
+func (c failingCache) SetIfNotExists(item cache.Item, serialize bool, compress bool, ttl int32) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+var _ = gk.Describe("Phone verification", func() {
+	gk.It("should use only the SMS sender configured", func() {
+		tests := []struct {
+			name   string
+			sender string
+			found  bool
+		}{
+			{"none configured", "", false},
+			{"log for local use", appconstant.SMS_SENDER_LOG, true},
+			{"not registered", "gateway", false},
+		}
+		for _, test := range tests {
+			sender, err := initSmsSender(test.sender)
+			if !test.found {
+				gm.Expect(err).NotTo(gm.BeNil(), test.name)
+				gm.Expect(sender).To(gm.BeNil(), test.name)
+				continue
+			}
+			gm.Expect(err).To(gm.BeNil(), test.name)
+			gm.Expect(sender).To(gm.Equal(LogSmsSender{}), test.name)
+		}
+	})
+
+	gk.It("should generate a numeric code of the configured length", func() {
+		for _, length := range []int{4, appconstant.DEFAULT_OTP_LENGTH, 8} {
+			code, err := generateOtp(length)
+			gm.Expect(err).To(gm.BeNil())
+			gm.Expect(code).To(gm.MatchRegexp(fmt.Sprintf("^[0-9]{%d}$", length)))
+		}
+		// The codes are random
+		codes := make(map[string]bool)
+		for i := 0; i < 20; i++ {
+			code, _ := generateOtp(appconstant.DEFAULT_OTP_LENGTH)
+			codes[code] = true
+		}
+		gm.Expect(len(codes)).To(gm.BeNumerically(">", 1))
+	})
+
+	gk.It("should store a hash of the code bound to the address it was sent for", func() {
+		hash := hashOtp("1773895", "7", "123456")
+		gm.Expect(hash).To(gm.MatchRegexp("^[0-9a-f]{64}$"))
+		gm.Expect(hash).NotTo(gm.ContainSubstring("123456"))
+		gm.Expect(hashOtp("1773895", "7", "123456")).To(gm.Equal(hash))
+
+		tests := []struct {
+			name      string
+			userID    string
+			addressID string
+			code      string
+		}{
+			{"another code", "1773895", "7", "123457"},
+			{"another address", "1773895", "8", "123456"},
+			{"another user", "1773896", "7", "123456"},
+			// The ids are kept apart so that they can not be shifted into each other
+			{"ids shifted", "177389", "57", "123456"},
+		}
+		for _, test := range tests {
+			gm.Expect(hashOtp(test.userID, test.addressID, test.code)).NotTo(gm.Equal(hash), test.name)
+		}
+	})
+
+	gk.It("should allow only the configured attempts of a code", func() {
+		keys := getOtpAttemptCacheKeys("1773895", "7", appconstant.DEFAULT_OTP_MAX_ATTEMPTS)
+		gm.Expect(keys).To(gm.HaveLen(appconstant.DEFAULT_OTP_MAX_ATTEMPTS))
+		distinct := make(map[string]bool)
+		for _, key := range keys {
+			distinct[key] = true
+		}
+		gm.Expect(distinct).To(gm.HaveLen(appconstant.DEFAULT_OTP_MAX_ATTEMPTS))
+		gm.Expect(getOtpAttemptCacheKeys("1773895", "8", 1)[0]).NotTo(gm.Equal(keys[0]))
+
+		cacheObj := new(memoryCache)
+		cacheObj.Init(nil)
+		for i := 0; i < appconstant.DEFAULT_OTP_MAX_ATTEMPTS; i++ {
+			claimed, err := claimOtpAttempt(cacheObj, keys, appconstant.DEFAULT_OTP_EXPIRY_SECONDS)
+			gm.Expect(err).To(gm.BeNil())
+			gm.Expect(claimed).To(gm.BeTrue(), fmt.Sprintf("attempt %d", i+1))
+		}
+		claimed, err := claimOtpAttempt(cacheObj, keys, appconstant.DEFAULT_OTP_EXPIRY_SECONDS)
+		gm.Expect(err).To(gm.BeNil())
+		gm.Expect(claimed).To(gm.BeFalse())
+
+		// A new code resets the attempts
+		cacheObj.DeleteBatch(keys)
+		claimed, _ = claimOtpAttempt(cacheObj, keys, appconstant.DEFAULT_OTP_EXPIRY_SECONDS)
+		gm.Expect(claimed).To(gm.BeTrue())
+	})
+
+	gk.It("should fail the attempt when the cache fails", func() {
+		claimed, err := claimOtpAttempt(failingCache{new(memoryCache)}, []string{"attempt_1"}, appconstant.DEFAULT_OTP_EXPIRY_SECONDS)
+		gm.Expect(err).NotTo(gm.BeNil())
+		gm.Expect(claimed).To(gm.BeFalse())
+	})
+})
@@ -0,0 +1,165 @@
+package address
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jabong/florest-core/src/common/logger/formatter"
+	"github.com/jabong/florest-core/src/common/logger/message"
+)
+
+//piiPolicy is how the personal data is masked in the logs and the debug data, by field. Fields are
+//matched case insensitively and without underscores, so first_name and FirstName are the same field
+var piiPolicy = map[string]func(string) string{
+	"phone":          maskPhone,
+	"alternatephone": maskPhone,
+	"receiverphone":  maskPhone,
+	"name":           maskName,
+	"firstname":      maskName,
+	"lastname":       maskName,
+	"receivername":   maskName,
+	"address1":       maskLine,
+	"address2":       maskLine,
+	"house":          maskLine,
+	"housenumber":    maskLine,
+	"building":       maskLine,
+	"street":         maskLine,
+	"landmark":       maskLine,
+	//q has the data sent to the encryption service and, like query, the search of the list API
+	"q":     maskLine,
+	"query": maskLine,
+	//the verification code of a phone, as in the params
+	"otp": maskLine,
+}
+
+//piiValuePolicy masks fields only in json, sql and url queries, not in %+v. code is the verification code
+//in the requests, the Code of an error printed with %+v is not personal data
+var piiValuePolicy = map[string]func(string) string{
+	"code": maskLine,
+}
+
+var (
+	//"first_name":"value" of json
+	jsonFieldPattern = regexp.MustCompile(`"(\w+)"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+	//address2='value' of sql
+	sqlFieldPattern = regexp.MustCompile(`\b(\w+)\s*=\s*'((?:[^'\\]|\\.)*)'`)
+	//?q=value of urls
+	queryFieldPattern = regexp.MustCompile(`[?&](\w+)=([^&#\s]*)`)
+	//FirstName:value of structs and maps printed with %+v, the value runs up to the next field or the end
+	//of the struct
+	structFieldPattern = regexp.MustCompile(`[{\[\s](\w+):`)
+)
+
+var redactionOnce sync.Once
+
+//initRedaction masks the personal data of every log message
+func initRedaction() {
+	redactionOnce.Do(func() {
+		formatter.AddHook(redactLogMsg)
+	})
+}
+
+//redactLogMsg masks the personal data in the message and the uri of a log
+func redactLogMsg(msg *message.LogMsg) {
+	msg.Message = redactPII(msg.Message)
+	msg.URI = redactPII(msg.URI)
+	for i, trace := range msg.StackTraces {
+		msg.StackTraces[i] = redactPII(trace)
+	}
+}
+
+//redactDebug returns the debug info with the personal data masked
+func redactDebug(debug *Debug) []DebugInfo {
+	res := make([]DebugInfo, len(debug.MessageStack))
+	for i, v := range debug.MessageStack {
+		res[i] = DebugInfo{Key: v.Key, Value: redactPII(v.Value)}
+	}
+	return res
+}
+
+//redactPII masks the values of the fields of the policy found in s as json, sql, url query or %+v
+func redactPII(s string) string {
+	if s == "" {
+		return s
+	}
+	s = redactMatches(s, jsonFieldPattern)
+	s = redactMatches(s, sqlFieldPattern)
+	s = redactMatches(s, queryFieldPattern)
+	return redactStructFields(s)
+}
+
+//redactMatches masks the value, the second group, of the matches whose field, the first group, is in the policy
+func redactMatches(s string, pattern *regexp.Regexp) string {
+	var buf bytes.Buffer
+	last := 0
+	for _, m := range pattern.FindAllStringSubmatchIndex(s, -1) {
+		field := normalizeField(s[m[2]:m[3]])
+		mask, ok := piiPolicy[field]
+		if !ok {
+			mask, ok = piiValuePolicy[field]
+		}
+		if !ok {
+			continue
+		}
+		buf.WriteString(s[last:m[4]])
+		buf.WriteString(mask(s[m[4]:m[5]]))
+		last = m[5]
+	}
+	if last == 0 {
+		return s
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
+
+//redactStructFields masks the fields of the policy in structs and maps printed with %+v
+func redactStructFields(s string) string {
+	matches := structFieldPattern.FindAllStringSubmatchIndex(s, -1)
+	var buf bytes.Buffer
+	last := 0
+	for i, m := range matches {
+		mask, ok := piiPolicy[normalizeField(s[m[2]:m[3]])]
+		if !ok || m[1] < last {
+			continue
+		}
+		end := len(s)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		if j := strings.IndexAny(s[m[1]:end], "}]"); j >= 0 {
+			end = m[1] + j
+		}
+		buf.WriteString(s[last:m[1]])
+		buf.WriteString(mask(s[m[1]:end]))
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
+
+func normalizeField(field string) string {
+	return strings.ToLower(strings.Replace(field, "_", "", -1))
+}
+
+//maskName keeps the first letter of a name
+func maskName(name string) string {
+	if name == "" {
+		return name
+	}
+	r, _ := utf8.DecodeRuneInString(name)
+	return string(r) + "***"
+}
+
+//maskLine hides an address line, not even its length is kept
+func maskLine(line string) string {
+	if line == "" {
+		return line
+	}
+	return "***"
+}
@@ -0,0 +1,42 @@
+package address
+
+import (
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("PII redaction", func() {
+	gk.It("should mask the personal data of the logs and the debug data", func() {
+		tests := []struct {
+			name     string
+			s        string
+			redacted string
+		}{
+			{"empty", "", ""},
+			{"nothing personal", "Address Service Accessor Initialize", "Address Service Accessor Initialize"},
+			{"json", `{"first_name":"Asha","phone":"9876543210","city":"Pune"}`,
+				`{"first_name":"A***","phone":"******3210","city":"Pune"}`},
+			{"json with spaces", `{"LastName" : "Rao", "Address1": "12 MG Road"}`,
+				`{"LastName" : "R***", "Address1": "***"}`},
+			{"json escaped quotes", `{"address1":"12 \"Sunrise\" Apartments","city":"Pune"}`,
+				`{"address1":"***","city":"Pune"}`},
+			{"json verification code", `{"code":"123456"}`, `{"code":"***"}`},
+			{"json error code", `{"code":1501,"message":"invalid"}`, `{"code":1501,"message":"invalid"}`},
+			{"sql", `UPDATE customer_address SET first_name='Asha', address1='12 MG Road', city='Pune'`,
+				`UPDATE customer_address SET first_name='A***', address1='***', city='Pune'`},
+			{"sql escaped quote", `SET landmark='Rao\'s House', postcode='411001'`,
+				`SET landmark='***', postcode='411001'`},
+			{"url query", `/address/V1/address/?q=mg+road&limit=10`, `/address/V1/address/?q=***&limit=10`},
+			{"url query of the encryption service", `http://enc/decrypt?q=98765,12345`, `http://enc/decrypt?q=***`},
+			{"url verification code", `/confirm?code=123456&id=7`, `/confirm?code=***&id=7`},
+			{"struct", `{FirstName:Asha LastName:Rao Phone:9876543210 City:Pune}`,
+				`{FirstName:A*** LastName:R*** Phone:******3210 City:Pune}`},
+			{"nested struct", `&{RequestId: QueryParams:{Limit:10 Address:{FirstName:Asha Address1:12 MG Road Phone:9876543210} Otp:123456} Buckets:map[]}`,
+				`&{RequestId: QueryParams:{Limit:10 Address:{FirstName:A*** Address1:*** Phone:******3210} Otp:***} Buckets:map[]}`},
+			{"struct error code", `&{Code:1501 Message:invalid}`, `&{Code:1501 Message:invalid}`},
+		}
+		for _, test := range tests {
+			gm.Expect(redactPII(test.s)).To(gm.Equal(test.redacted), test.name)
+		}
+	})
+})
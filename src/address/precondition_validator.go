@@ -0,0 +1,75 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+	"strings"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//PreconditionValidator rejects a change of an address if the If-Match header does not match the
+//current entity tag of the address, so that a client can not overwrite a version it has not seen
+type PreconditionValidator struct {
+	id string
+}
+
+func (n *PreconditionValidator) SetID(id string) {
+	n.id = id
+}
+
+func (n PreconditionValidator) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n PreconditionValidator) Name() string {
+	return "PreconditionValidator"
+}
+
+func (n PreconditionValidator) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("PreconditionValidator")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"PreconditionValidator_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Precondition Validator", "Precondition Validator-Execute")
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+	ifMatch := strings.TrimSpace(appHTTPReq.GetHeaderParameter(appconstant.IF_MATCH))
+	if ifMatch == "" {
+		return io, nil
+	}
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("PreconditionValidator. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	debugInfo := new(Debug)
+	version, err := getAddressVersion(params, debugInfo)
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("PreconditionValidator: could not get the version of address %d - %v", params.QueryParams.AddressId, err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: "Could not check If-Match"}
+	}
+	if version == "" {
+		// Let the executor report the missing address
+		return io, nil
+	}
+	etag := getAddressETag(fmt.Sprintf("%d", params.QueryParams.AddressId), version)
+	if !etagMatches(ifMatch, etag) {
+		return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: errAddressChanged.Error()}
+	}
+	if ifMatch != "*" {
+		// The address may change before it is written, the update is written only if it has not
+		params.QueryParams.IfMatchVersion = version
+	}
+	return io, nil
+}
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type AddressPreferencesAPI struct {
+}
+
+func (a *AddressPreferencesAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "GET",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/preferences",
+	}
+}
+
+func (a *AddressPreferencesAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("AddressPreferences")
+}
+
+func (a *AddressPreferencesAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *AddressPreferencesAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *AddressPreferencesAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
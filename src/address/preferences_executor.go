@@ -0,0 +1,75 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//AddressPreferencesExecutor gets or replaces the delivery preferences of an address
+type AddressPreferencesExecutor struct {
+	id string
+}
+
+func (n *AddressPreferencesExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n AddressPreferencesExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n AddressPreferencesExecutor) Name() string {
+	return "AddressPreferencesExecutor"
+}
+
+func (n AddressPreferencesExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AddressPreferencesExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AddressPreferencesExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Address Preferences Executor", "Address Preferences Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("AddressPreferencesExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+
+	debugInfo := new(Debug)
+	var addressResult *AddressResult
+	var err error
+	if appHTTPReq.HTTPVerb == utilHttp.PUT {
+		addressResult, err = UpdateAddressPreferences(params, debugInfo)
+	} else {
+		addressResult, err = GetAddressPreferences(params, debugInfo)
+	}
+	addDebugContents(io, debugInfo)
+	if err == errAddressChanged {
+		return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: err.Error()}
+	}
+	if err == errAddressNotFound {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("There is some error occured while processing the address preferences %v", err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting address preferences result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
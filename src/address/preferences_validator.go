@@ -0,0 +1,61 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//PreferencesValidator validates the delivery preferences in the body of a PUT on the preferences of an address
+type PreferencesValidator struct {
+	id string
+}
+
+func (n *PreferencesValidator) SetID(id string) {
+	n.id = id
+}
+
+func (n PreferencesValidator) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n PreferencesValidator) Name() string {
+	return "PreferencesValidator"
+}
+
+func (n PreferencesValidator) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("PreferencesValidator")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"PreferencesValidator_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Preferences Validator", "Preferences Validator-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("PreferencesValidator. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	rp, _ := io.IOData.Get(constants.Request)
+	appHTTPReq, _ := rp.(*utilHttp.Request)
+	bodyParam, err := appHTTPReq.GetBodyParameter()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid Body Param: %v", err), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	prefs, err := parseDeliveryPreferences([]byte(bodyParam))
+	if err != nil {
+		logger.Error("PreferencesValidator: Request params validation failed." + err.Error())
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: err.Error()}
+	}
+	params.QueryParams.Preferences = prefs
+	return io, nil
+}
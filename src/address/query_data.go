@@ -1,14 +1,46 @@
 package address
 
 import (
+	"context"
+
 	utilHttp "github.com/jabong/florest-core/src/common/utils/http"
 )
 
 type RequestParams struct {
-	RequestId      string
-	QueryParams    QueryParams
-	Buckets        map[string]string
+	RequestId   string
+	QueryParams QueryParams
+	Buckets     map[string]string
+	//Experiments are the variants of the experiments the request is in, by experiment
+	Experiments    map[string]string
 	RequestContext utilHttp.RequestContext
+	Admin          *AdminContext
+	//APIVersion is the version of the API the request is for, e.g. V1
+	APIVersion string
+	//ctx has the span and the deadline of the request, the db, cache and encryption service calls are traced as
+	//its children and cancelled with it
+	ctx context.Context
+}
+
+//Context gets the context the calls made for the request are traced in
+func (p *RequestParams) Context() context.Context {
+	if p == nil || p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+//withContext gets a copy of the params whose calls are made with the context of a node, so that they are
+//cancelled at the timeout of the node. The params shared by the nodes keep the context of the request
+func (p *RequestParams) withContext(ctx context.Context) *RequestParams {
+	nodeParams := *p
+	nodeParams.ctx = ctx
+	return &nodeParams
+}
+
+//AdminContext identifies the support agent acting on a customer's address book
+type AdminContext struct {
+	AgentId string
+	Reason  string
 }
 
 type QueryParams struct {
@@ -17,6 +49,27 @@ type QueryParams struct {
 	AddressType string
 	AddressId   int
 	Postcode    int
-	Default     int
-	Address     AddressRequest
+	City        string
+	Region      string
+	Country     string
+	IsOffice    string
+	LabelType   string
+	Query       string
+	//IncludePreferences returns the delivery preferences with the address list
+	IncludePreferences bool
+	Default            int
+	Address            AddressRequest
+	//PatchFields are the fields present in a PATCH body, true if set and false if cleared with null
+	PatchFields map[string]bool
+	//IfMatchVersion is the version of the address matched by the If-Match of the request, the update is
+	//only written if the address still has it
+	IfMatchVersion string
+	//Preferences are the delivery preferences of a PUT on the preferences of an address
+	Preferences *DeliveryPreferences
+	//Otp is the code sent to verify the phone of an address
+	Otp string
+	//Reassign deletes a default address, another address of the customer becomes the default
+	Reassign bool
+	//SuccessorId is the address to become the default, the most recently used address if not given
+	SuccessorId int
 }
@@ -85,7 +85,9 @@ func (a QueryTermEnhancer) Execute(io workflow.WorkFlowData) (workflow.WorkFlowD
 		params.QueryParams.AddressType = appconstant.SHIPPING
 	}
 
-	if appHTTPReq.HTTPVerb == "DELETE" || appHTTPReq.HTTPVerb == "PUT" || appHTTPReq.HTTPVerb == "GET" {
+	// POST on an address sub-resource, e.g. restore, carries the address id in the path
+	isAddressPost := appHTTPReq.HTTPVerb == "POST" && appHTTPReq.GetPathParameter(appconstant.URLPARAM_ADDRESSID) != ""
+	if appHTTPReq.HTTPVerb == "DELETE" || appHTTPReq.HTTPVerb == "PUT" || appHTTPReq.HTTPVerb == "PATCH" || appHTTPReq.HTTPVerb == "GET" || isAddressPost {
 		// Update default billing/shipping address case
 		if len(*appHTTPReq.PathParameters) == 2 {
 			validateAndSetParamsForUpdate(&params, appHTTPReq)
@@ -103,7 +105,7 @@ func (a QueryTermEnhancer) Execute(io workflow.WorkFlowData) (workflow.WorkFlowD
 	return io, nil
 }
 
-//updateParamsWithBuckets updates buckets to params
+//updateParamsWithBuckets updates buckets and the experiment variants they put the request in to params
 func updateParamsWithBuckets(params *RequestParams, io workflow.WorkFlowData) {
 	rc, _ := io.ExecContext.Get(constants.RequestContext)
 	bucketMap, err := io.ExecContext.GetBuckets()
@@ -111,9 +113,11 @@ func updateParamsWithBuckets(params *RequestParams, io workflow.WorkFlowData) {
 		logger.Warning(fmt.Sprintf("err in retrieving buckets : %v", err), rc)
 	}
 	params.Buckets = bucketMap
+	params.Experiments = getExperimentVariants(bucketMap)
+	recordExposures(params.Experiments, rc)
 }
 
-//updateParamsWithRequestContext updates request context to params
+//updateParamsWithRequestContext updates request context, the API version and the span of the request to params
 func updateParamsWithRequestContext(params *RequestParams, io workflow.WorkFlowData) {
 	rc, err := io.ExecContext.Get(constants.RequestContext)
 	if err != nil { //no need to return error as its not fatal issue
@@ -122,6 +126,10 @@ func updateParamsWithRequestContext(params *RequestParams, io workflow.WorkFlowD
 	if v, ok := rc.(utilHttp.RequestContext); ok {
 		params.RequestContext = v
 	}
+	version, _ := io.IOData.Get(constants.Version)
+	params.APIVersion, _ = version.(string)
+	//The enhancer runs without a timeout of its own, its context is the one of the request
+	params.ctx = io.Context()
 }
 
 func validateAndSetParams(params *RequestParams, httpReq *utilHttp.Request) error {
@@ -134,6 +142,14 @@ func validateAndSetParams(params *RequestParams, httpReq *utilHttp.Request) erro
 			return errors.New("AddressType should be one of all, billing, shipping or other")
 		}
 		params.QueryParams.AddressType = val
+		// Sub-resources of an address, e.g. history
+		if val = httpReq.GetPathParameter(appconstant.URLPARAM_ADDRESSID); val != "" {
+			addressID, err := strconv.Atoi(val)
+			if err != nil {
+				return errors.New("Id is missing or not a number")
+			}
+			params.QueryParams.AddressId = addressID
+		}
 		return nil
 	}
 	val := httpReq.GetPathParameter(appconstant.URLPARAM_ADDRESSID)
@@ -142,6 +158,33 @@ func validateAndSetParams(params *RequestParams, httpReq *utilHttp.Request) erro
 		return errors.New("Id is missing or not a number")
 	}
 	params.QueryParams.AddressId = addressID
+	if httpReq.HTTPVerb == "DELETE" {
+		return validateAndSetReassignParams(params, httpReq)
+	}
+	return nil
+}
+
+//validateAndSetReassignParams reads whether a delete reassigns the defaults of the address, and to which address.
+//Giving a successor implies reassign
+func validateAndSetReassignParams(params *RequestParams, httpReq *utilHttp.Request) error {
+	switch reassign := strings.ToLower(strings.TrimSpace(httpReq.OriginalRequest.FormValue(appconstant.URLPARAM_REASSIGN))); reassign {
+	case "", "false", "0":
+	case "true", "1":
+		params.QueryParams.Reassign = true
+	default:
+		return fmt.Errorf("Invalid value for %s, it should be true or false", appconstant.URLPARAM_REASSIGN)
+	}
+	if val := strings.TrimSpace(httpReq.OriginalRequest.FormValue(appconstant.URLPARAM_SUCCESSOR)); val != "" {
+		successorID, err := strconv.Atoi(val)
+		if err != nil || successorID <= 0 {
+			return fmt.Errorf("Invalid value for %s, it should be an address id", appconstant.URLPARAM_SUCCESSOR)
+		}
+		if successorID == params.QueryParams.AddressId {
+			return errors.New("The address being deleted can not be its own successor")
+		}
+		params.QueryParams.Reassign = true
+		params.QueryParams.SuccessorId = successorID
+	}
 	return nil
 }
 
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	constants "github.com/jabong/florest-core/src/common/constants"
 	logger "github.com/jabong/florest-core/src/common/logger"
@@ -59,7 +61,8 @@ func (a QueryTermValidator) Execute(io workflow.WorkFlowData) (workflow.WorkFlow
 
 func validateAndSetURLParams(params *RequestParams, httpReq *http.Request) error {
 	var (
-		limit  = appconstant.DEFAULT_LIMIT
+		t      = getTunables()
+		limit  = t.defaultLimit
 		offset = appconstant.DEFAULT_OFFSET
 		err    error
 	)
@@ -69,8 +72,8 @@ func validateAndSetURLParams(params *RequestParams, httpReq *http.Request) error
 			return errors.New("Limit must be a valid number")
 		}
 	}
-	if limit > appconstant.MAX_LIMIT {
-		limit = appconstant.DEFAULT_LIMIT
+	if limit > t.maxLimit {
+		limit = t.defaultLimit
 	}
 	params.QueryParams.Limit = limit
 	if httpReq.FormValue(appconstant.URLPARAM_OFFSET) != "" {
@@ -80,6 +83,51 @@ func validateAndSetURLParams(params *RequestParams, httpReq *http.Request) error
 		}
 	}
 	params.QueryParams.Offset = offset
+	return validateAndSetFilterParams(params, httpReq)
+}
+
+//validateAndSetFilterParams sets the optional filters of the address list
+func validateAndSetFilterParams(params *RequestParams, httpReq *http.Request) error {
+	if postcode := strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_POSTCODE)); postcode != "" {
+		postcodeLength := getTunables().postcodeLength
+		p, err := strconv.Atoi(postcode)
+		if err != nil || len(postcode) != postcodeLength {
+			return fmt.Errorf("Postcode must be a %d digit number", postcodeLength)
+		}
+		params.QueryParams.Postcode = p
+	}
+	if country := strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_COUNTRY)); country != "" {
+		if _, err := strconv.Atoi(country); err != nil {
+			return errors.New("Country must be a number")
+		}
+		params.QueryParams.Country = country
+	}
+	switch isOffice := strings.ToLower(strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_ISOFFICE))); isOffice {
+	case "":
+	case appconstant.HOME:
+		params.QueryParams.IsOffice = "0"
+	case appconstant.OFFICE:
+		params.QueryParams.IsOffice = "1"
+	default:
+		return errors.New("Invalid address_type. Possible values are home, office")
+	}
+	if labelType := strings.ToLower(strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_LABELTYPE))); labelType != "" {
+		if !isLabelType(labelType) {
+			return errors.New("Invalid label_type. Possible values are home, work, other")
+		}
+		params.QueryParams.LabelType = labelType
+	}
+	params.QueryParams.City = strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_CITY))
+	params.QueryParams.Region = strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_REGION))
+	params.QueryParams.Query = strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_QUERY))
+	if include := strings.TrimSpace(httpReq.FormValue(appconstant.URLPARAM_INCLUDE)); include != "" {
+		for _, value := range strings.Split(include, ",") {
+			if strings.ToLower(strings.TrimSpace(value)) != appconstant.INCLUDE_PREFERENCES {
+				return errors.New("Invalid include. Possible values are preferences")
+			}
+		}
+		params.QueryParams.IncludePreferences = true
+	}
 	return nil
 }
 
@@ -0,0 +1,24 @@
+package address
+
+//ReceiverContact is the person deliveries to an address are handed to, e.g. for gift orders or
+//for the pickup of an exchange or a return
+type ReceiverContact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+//newReceiverContact returns the receiver of an address, nil if the address has none
+func newReceiverContact(name string, phone string) *ReceiverContact {
+	if name == "" && phone == "" {
+		return nil
+	}
+	return &ReceiverContact{Name: name, Phone: phone}
+}
+
+//getEncryptedValue returns the encrypted value to store, NULL if the field is not given
+func getEncryptedValue(value string, encrypted string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return encrypted
+}
@@ -0,0 +1,107 @@
+package address
+
+import (
+	"common/appconstant"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	"github.com/jabong/florest-core/src/components/sqldb"
+)
+
+//RegionMetadata is a region an address can be in
+type RegionMetadata struct {
+	Id        string
+	Name      string
+	CountryId string
+}
+
+//regionMetadataCache keeps the regions in memory, they seldom change
+var regionMetadataCache struct {
+	sync.RWMutex
+	regions  map[string]*RegionMetadata
+	loadedAt time.Time
+}
+
+//getRegionMetadata gets the regions by id, they are read again once older than REGION_METADATA_TTL
+func getRegionMetadata(ctx context.Context, debug *Debug) (map[string]*RegionMetadata, error) {
+	if regions := getLoadedRegionMetadata(); regions != nil {
+		return regions, nil
+	}
+	// The regions are read without the lock, the requests finding them loaded do not wait for the read
+	regions, err := readRegionMetadata(ctx, debug)
+	if err != nil {
+		return nil, err
+	}
+	regionMetadataCache.Lock()
+	regionMetadataCache.regions = regions
+	regionMetadataCache.loadedAt = time.Now()
+	regionMetadataCache.Unlock()
+	return regions, nil
+}
+
+//getLoadedRegionMetadata gets the regions in memory without reading them, nil if they were never read or
+//are older than REGION_METADATA_TTL
+func getLoadedRegionMetadata() map[string]*RegionMetadata {
+	regionMetadataCache.RLock()
+	defer regionMetadataCache.RUnlock()
+	if time.Since(regionMetadataCache.loadedAt) >= appconstant.REGION_METADATA_TTL*time.Second {
+		return nil
+	}
+	return regionMetadataCache.regions
+}
+
+func readRegionMetadata(ctx context.Context, debug *Debug) (map[string]*RegionMetadata, error) {
+	db, derr := sqldb.GetWithContext(ctx, "mysdb")
+	if derr != nil {
+		return nil, derr
+	}
+	prof := profiler.NewProfiler()
+	prof.StartProfile("AddressModel#readRegionMetadata")
+	defer func() {
+		prof.EndProfileWithMetric([]string{"AddressModel#readRegionMetadata"})
+	}()
+
+	sql := `SELECT id_customer_address_region, name, fk_country FROM customer_address_region`
+	debug.MessageStack = append(debug.MessageStack, DebugInfo{Key: "GetRegionMetadataSql", Value: sql})
+	rows, qerr := db.Query(sql)
+	if qerr != nil {
+		logger.Error(fmt.Sprintf("Mysql Error while getting data from customer_address_region |%s|%s|%s", appconstant.MYSQL_ERROR, qerr.Error(), "customer_address_region"))
+		return nil, qerr
+	}
+	defer rows.Close()
+	regions := make(map[string]*RegionMetadata)
+	for rows.Next() {
+		region := new(RegionMetadata)
+		if err := rows.Scan(&region.Id, &region.Name, &region.CountryId); err != nil {
+			logger.Error(fmt.Sprintf("Mysql Row Error while getting row from customer_address_region table %s", err))
+			return nil, err
+		}
+		regions[region.Id] = region
+	}
+	return regions, rows.Err()
+}
+
+//setRegionNames sets the names of the regions of the addresses, an address list or a single address
+func setRegionNames(addressList interface{}, regions map[string]*RegionMetadata) {
+	switch addresses := addressList.(type) {
+	case map[string]*AddressResponse:
+		for _, address := range addresses {
+			setRegionName(address, regions)
+		}
+	case *AddressResponse:
+		setRegionName(addresses, regions)
+	}
+}
+
+func setRegionName(address *AddressResponse, regions map[string]*RegionMetadata) {
+	if address == nil {
+		return
+	}
+	if region, ok := regions[address.AddressRegion]; ok {
+		address.RegionName = region.Name
+	}
+}
@@ -0,0 +1,50 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//RegionMetadataLoader reads the regions while the addresses are read on another forked path.
+//The addresses are still listed when the regions can not be read
+type RegionMetadataLoader struct {
+	id string
+}
+
+func (n *RegionMetadataLoader) SetID(id string) {
+	n.id = id
+}
+
+func (n RegionMetadataLoader) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n RegionMetadataLoader) Name() string {
+	return "RegionMetadataLoader"
+}
+
+func (n RegionMetadataLoader) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("RegionMetadataLoader")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"RegionMetadataLoader_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Region Metadata Loader", "Region Metadata Loader-Execute")
+	debugInfo := new(Debug)
+	regions, err := getRegionMetadata(io.Context(), debugInfo)
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("RegionMetadataLoader: could not read the regions - %v", err), rc)
+		return io, nil
+	}
+	io.IOData.Set(appconstant.IO_REGION_METADATA, regions)
+	return io, nil
+}
@@ -0,0 +1,66 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//RestoreAddressExecutor restores an address deleted within the restore window
+type RestoreAddressExecutor struct {
+	id string
+}
+
+func (n *RestoreAddressExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n RestoreAddressExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (a RestoreAddressExecutor) Name() string {
+	return "RestoreAddressExecutor"
+}
+
+func (a RestoreAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("RestoreAddressExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"RestoreAddressExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Restore Address Executor", "Restore Address Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("RestoreAddressExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+	if params.QueryParams.AddressId == 0 {
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: "Id is missing or not a number"}
+	}
+
+	debugInfo := new(Debug)
+	restoreResult, err := RestoreAddress(params, debugInfo)
+	addDebugContents(io, debugInfo)
+	if err == errAddressNotRestorable {
+		return io, &constants.AppError{Code: appconstant.AddressNotRestorableErrorCode, Message: err.Error()}
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("There is some error occured while restoring the address %v", err), rc)
+		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, restoreResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting restore address result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
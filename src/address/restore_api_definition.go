@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type RestoreAddressAPI struct {
+}
+
+func (a *RestoreAddressAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "POST",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/restore",
+	}
+}
+
+func (a *RestoreAddressAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("RestoreAddress")
+}
+
+func (a *RestoreAddressAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *RestoreAddressAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *RestoreAddressAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 // Remove all other unrecognised characters apart from
 var illegalChars = regexp.MustCompile(`[^[:alnum:]-.,/]`)
 var illegalCharsForName = regexp.MustCompile(`[^[:alpha:]-.]`)
+var illegalCharsForLabel = regexp.MustCompile(`[^[:alnum:]-.,/']`)
 
 // A list of characters we consider separators in normal strings and replace with our canonical separator - rather than removing.
 var (
@@ -146,3 +147,67 @@ func cleanString(s string, r *regexp.Regexp) string {
 	s = strings.TrimSpace(s)
 	return s
 }
+
+// Characters dropped or treated as separators when normalizing an address, so that "M.G. Rd" and "MG Rd" compare equal
+var (
+	addressDots        = regexp.MustCompile(`\.`)
+	addressPunctuation = regexp.MustCompile(`[-,/]`)
+)
+
+// Common abbreviations in address lines and their expansions, the default of the tunables
+var addressAbbreviations = map[string]string{
+	"rd":   "road",
+	"st":   "street",
+	"ln":   "lane",
+	"nr":   "near",
+	"opp":  "opposite",
+	"apt":  "apartment",
+	"apts": "apartment",
+	"bldg": "building",
+	"blk":  "block",
+	"sec":  "sector",
+	"mkt":  "market",
+	"ave":  "avenue",
+	"soc":  "society",
+	"hsg":  "housing",
+	"extn": "extension",
+	"ext":  "extension",
+}
+
+// Words which do not tell two addresses apart, the default of the tunables
+var addressFillerWords = map[string]bool{
+	"flat":      true,
+	"apartment": true,
+	"house":     true,
+	"h":         true,
+	"no":        true,
+	"number":    true,
+	"plot":      true,
+}
+
+// normalizeAddress reduces an address to a canonical key for comparison. Case, whitespace, punctuation,
+// common abbreviations and filler words are ignored, so "Flat 3B, MG Rd" and "3B MG Road" give the same key.
+// The abbreviations and filler words can be configured in the tunables.
+func normalizeAddress(s string) string {
+	t := getTunables()
+	s = strings.ToLower(cleanString(s, illegalChars))
+	s = addressDots.ReplaceAllString(s, "")
+	s = addressPunctuation.ReplaceAllString(s, " ")
+	words := []string{}
+	for _, word := range strings.Fields(s) {
+		if val, ok := t.abbreviations[word]; ok {
+			word = val
+		}
+		if t.fillerWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+	// The words are kept apart, "1 23" and "12 3" are different addresses
+	return strings.Join(words, " ")
+}
+
+// sanitizeLabel cleans the name a user gives to an address, keeping apostrophes as in "Mom's place".
+func sanitizeLabel(s string) string {
+	return cleanString(s, illegalCharsForLabel)
+}
@@ -0,0 +1,33 @@
+package address
+
+import (
+	gk "github.com/onsi/ginkgo"
+	gm "github.com/onsi/gomega"
+)
+
+var _ = gk.Describe("Address normalization", func() {
+	gk.It("should reduce an address to the same key however it is written", func() {
+		tests := []struct {
+			name    string
+			address string
+			key     string
+		}{
+			{"empty", "", ""},
+			{"case and spaces", "  12   MG   Road ", "12 mg road"},
+			{"punctuation", "12, MG Road - Camp/Pune", "12 mg road camp pune"},
+			{"dots", "12 M.G. Road", "12 mg road"},
+			{"abbreviations", "12 MG Rd, Opp City Mkt", "12 mg road opposite city market"},
+			{"abbreviation with a dot", "Sunrise Apts., Nr. Station", "sunrise near station"},
+			{"filler words", "Flat No 3B, House Number 7", "3b 7"},
+			{"abbreviation to a filler word", "Apt 4, Blk C", "4 block c"},
+		}
+		for _, test := range tests {
+			gm.Expect(normalizeAddress(test.address)).To(gm.Equal(test.key), test.name)
+		}
+	})
+
+	gk.It("should tell apart addresses differing only in where the words end", func() {
+		gm.Expect(normalizeAddress("Flat 1, 23 MG Road")).NotTo(gm.Equal(normalizeAddress("Flat 12, 3 MG Road")))
+		gm.Expect(normalizeAddress("Flat 3B, MG Rd")).To(gm.Equal(normalizeAddress("3B MG Road")))
+	})
+})
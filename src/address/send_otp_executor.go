@@ -0,0 +1,60 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+//SendOtpExecutor sends a code to verify the phone of an address
+type SendOtpExecutor struct {
+	id string
+}
+
+func (n *SendOtpExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n SendOtpExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n SendOtpExecutor) Name() string {
+	return "SendOtpExecutor"
+}
+
+func (n SendOtpExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("SendOtpExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"SendOtpExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Send Otp Executor", "Send Otp Executor-Execute")
+	p, _ := io.IOData.Get(appconstant.IO_REQUEST_PARAMS)
+	params, pOk := p.(*RequestParams)
+	if !pOk || params == nil {
+		logger.Error("SendOtpExecutor. invalid type of params")
+		return io, &constants.AppError{Code: constants.ParamsInValidErrorCode, Message: "invalid type of params"}
+	}
+
+	debugInfo := new(Debug)
+	addressResult, err := SendPhoneVerification(params, debugInfo)
+	addDebugContents(io, debugInfo)
+	if err != nil {
+		logger.Error(fmt.Sprintf("There is some error occured while sending the code for address %d %v", params.QueryParams.AddressId, err), rc)
+		return io, getPhoneVerificationAppError(err)
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting phone verification result to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
@@ -45,8 +45,20 @@ func registerAllAPIs() {
 	service.RegisterAPI(new(ListAddressAPI))
 	service.RegisterAPI(new(CreateAddressAPI))
 	service.RegisterAPI(new(UpdateAddressAPI))
+	service.RegisterAPI(new(PatchAddressAPI))
 	service.RegisterAPI(new(DeleteAddressAPI))
 	service.RegisterAPI(new(UpdateTypeAPI))
+	service.RegisterAPI(new(AddressHistoryAPI))
+	service.RegisterAPI(new(RestoreAddressAPI))
+	service.RegisterAPI(new(AddressPreferencesAPI))
+	service.RegisterAPI(new(UpdatePreferencesAPI))
+	service.RegisterAPI(new(VerifyPhoneAPI))
+	service.RegisterAPI(new(ConfirmPhoneAPI))
+	service.RegisterAPI(new(AdminListAddressAPI))
+	service.RegisterAPI(new(AdminViewAddressAPI))
+	service.RegisterAPI(new(AdminUpdateAddressAPI))
+	service.RegisterAPI(new(AdminDeleteAddressAPI))
+	service.RegisterAPI(new(AdminWorkflowsAPI))
 }
 
 func initTestConfig() {
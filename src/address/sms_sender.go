@@ -0,0 +1,58 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+	"sync"
+
+	logger "github.com/jabong/florest-core/src/common/logger"
+)
+
+//SmsSender sends a text message to a phone. Implementations are registered by name and picked
+//with the PhoneVerification.Sender config
+type SmsSender interface {
+	Send(phone string, message string) error
+}
+
+//LogSmsSender sends nothing, it only logs the masked phone a message was for. It is used only when
+//configured, for local use
+type LogSmsSender struct {
+}
+
+func (s LogSmsSender) Send(phone string, message string) error {
+	//The message has the code, it is not logged
+	logger.Info(fmt.Sprintf("LogSmsSender: message to %s not sent", maskPhone(phone)))
+	return nil
+}
+
+var (
+	smsSendersMutex sync.RWMutex
+	smsSenders      = map[string]SmsSender{
+		appconstant.SMS_SENDER_LOG: LogSmsSender{},
+	}
+	smsSenderObj SmsSender
+)
+
+//RegisterSmsSender makes an SMS sender available under name, it has to be called before Initialise
+func RegisterSmsSender(name string, sender SmsSender) {
+	smsSendersMutex.Lock()
+	defer smsSendersMutex.Unlock()
+	smsSenders[name] = sender
+}
+
+//initSmsSender returns the configured SMS sender. There is no default, the codes would not reach the users
+func initSmsSender(name string) (SmsSender, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no SMS sender is configured, set PhoneVerification.Sender to a registered sender, or to %s for local use", appconstant.SMS_SENDER_LOG)
+	}
+	if name == appconstant.SMS_SENDER_LOG {
+		logger.Warning("LogSmsSender: the verification codes are not sent, for local use only")
+	}
+	smsSendersMutex.RLock()
+	defer smsSendersMutex.RUnlock()
+	sender, ok := smsSenders[name]
+	if !ok {
+		return nil, fmt.Errorf("SMS sender %s is not registered", name)
+	}
+	return sender, nil
+}
@@ -0,0 +1,153 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/components/cache"
+	"github.com/jabong/florest-core/src/core/service"
+)
+
+//tunables are the limits and validation heuristics in effect, the defaults for what is not configured.
+//They are replaced as a whole when the dynamic config changes, a request reads them once
+type tunables struct {
+	defaultLimit          int
+	maxLimit              int
+	decryptBatchSize      int
+	phoneLength           int
+	postcodeLength        int
+	maxLabelLength        int
+	maxComponentLength    int
+	maxInstructionsLength int
+	maxDeliveryWindows    int
+	abbreviations         map[string]string
+	fillerWords           map[string]bool
+}
+
+var currentTunables atomic.Value
+
+//getTunables gets the tunables in effect, read from the config on first use
+func getTunables() *tunables {
+	if t, ok := currentTunables.Load().(*tunables); ok {
+		return t
+	}
+	var conf *appconfig.TunablesConfig
+	if appConfig, err := appconfig.GetAddressServiceConfig(); err == nil {
+		conf = appConfig.Tunables
+	}
+	t, err := newTunables(conf)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid tunables, using the defaults - %v", err))
+		t, _ = newTunables(nil)
+	}
+	currentTunables.Store(t)
+	return t
+}
+
+//newTunables sets the configured tunables over the defaults and validates them
+func newTunables(conf *appconfig.TunablesConfig) (*tunables, error) {
+	t := &tunables{
+		defaultLimit:          appconstant.DEFAULT_LIMIT,
+		maxLimit:              appconstant.MAX_LIMIT,
+		decryptBatchSize:      int(appconstant.BATCH_SIZE),
+		phoneLength:           appconstant.PHONE_LENGTH,
+		postcodeLength:        appconstant.POSTCODE_LENGTH,
+		maxLabelLength:        appconstant.MAX_LABEL_LENGTH,
+		maxComponentLength:    appconstant.MAX_COMPONENT_LENGTH,
+		maxInstructionsLength: appconstant.MAX_INSTRUCTIONS_LENGTH,
+		maxDeliveryWindows:    appconstant.MAX_DELIVERY_WINDOWS,
+		abbreviations:         addressAbbreviations,
+		fillerWords:           addressFillerWords,
+	}
+	if conf == nil {
+		return t, nil
+	}
+	for _, v := range []struct {
+		configured int
+		tunable    *int
+	}{
+		{conf.DefaultLimit, &t.defaultLimit},
+		{conf.MaxLimit, &t.maxLimit},
+		{conf.DecryptBatchSize, &t.decryptBatchSize},
+		{conf.PhoneLength, &t.phoneLength},
+		{conf.PostcodeLength, &t.postcodeLength},
+		{conf.MaxLabelLength, &t.maxLabelLength},
+		{conf.MaxComponentLength, &t.maxComponentLength},
+		{conf.MaxInstructionsLength, &t.maxInstructionsLength},
+		{conf.MaxDeliveryWindows, &t.maxDeliveryWindows},
+	} {
+		if v.configured < 0 {
+			return nil, errors.New("Tunables can not be negative")
+		}
+		if v.configured > 0 {
+			*v.tunable = v.configured
+		}
+	}
+	if conf.AddressAbbreviations != nil {
+		t.abbreviations = conf.AddressAbbreviations
+	}
+	if conf.AddressFillerWords != nil {
+		t.fillerWords = make(map[string]bool)
+		for _, word := range conf.AddressFillerWords {
+			t.fillerWords[word] = true
+		}
+	}
+	return t, t.validate()
+}
+
+func (t *tunables) validate() error {
+	if t.defaultLimit > t.maxLimit {
+		return fmt.Errorf("DefaultLimit %d is more than MaxLimit %d", t.defaultLimit, t.maxLimit)
+	}
+	// Phones are 10 digits in India, numbering plans allow at most 15
+	if t.phoneLength < 7 || t.phoneLength > 15 {
+		return fmt.Errorf("PhoneLength %d should be between 7 and 15", t.phoneLength)
+	}
+	if t.postcodeLength < 3 || t.postcodeLength > 10 {
+		return fmt.Errorf("PostcodeLength %d should be between 3 and 10", t.postcodeLength)
+	}
+	// The columns of the label, the components and the instructions are not longer
+	if t.maxLabelLength > appconstant.MAX_LABEL_LENGTH || t.maxComponentLength > appconstant.MAX_COMPONENT_LENGTH {
+		return fmt.Errorf("MaxLabelLength and MaxComponentLength should be at most %d and %d", appconstant.MAX_LABEL_LENGTH, appconstant.MAX_COMPONENT_LENGTH)
+	}
+	if t.maxInstructionsLength > appconstant.MAX_INSTRUCTIONS_LENGTH {
+		return fmt.Errorf("MaxInstructionsLength should be at most %d", appconstant.MAX_INSTRUCTIONS_LENGTH)
+	}
+	for abbreviation, expansion := range t.abbreviations {
+		if abbreviation == "" || expansion == "" {
+			return errors.New("AddressAbbreviations can not have an empty abbreviation or expansion")
+		}
+	}
+	return nil
+}
+
+//initDynamicConfig validates the tunables and reloads them when the dynamic config changes. The service
+//does not start with invalid tunables, an invalid dynamic config is not applied
+func initDynamicConfig(appConfig *appconfig.AddressServiceConfig) {
+	t, err := newTunables(appConfig.Tunables)
+	if err != nil {
+		panic("Invalid tunables " + err.Error())
+	}
+	currentTunables.Store(t)
+	service.RegisterDynamicConfigValidator(func(applicationConfig interface{}) error {
+		c, ok := applicationConfig.(*appconfig.AddressServiceConfig)
+		if !ok {
+			return fmt.Errorf("Unexpected config %T", applicationConfig)
+		}
+		_, terr := newTunables(c.Tunables)
+		return terr
+	})
+	service.RegisterDynamicConfigListener(func(applicationConfig interface{}) {
+		if c, ok := applicationConfig.(*appconfig.AddressServiceConfig); ok {
+			if t, terr := newTunables(c.Tunables); terr == nil {
+				currentTunables.Store(t)
+				logger.Info("Tunables reloaded from the dynamic config")
+			}
+		}
+	})
+	new(service.DynamicConfigManager).Initialize(appConfig, cache.Redis)
+}
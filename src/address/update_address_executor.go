@@ -50,6 +50,9 @@ func (a UpdateAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkF
 	if params.QueryParams.AddressId != 0 {
 		addressResult, err = UpdateAddress(params, debugInfo)
 		addDebugContents(io, debugInfo)
+		if err == errAddressChanged {
+			return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: err.Error()}
+		}
 		if err != nil {
 			logger.Error(fmt.Sprintf("There is some error occured while updating the address %v", err), rc)
 			return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
@@ -61,6 +64,11 @@ func (a UpdateAddressExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkF
 			logger.Error(fmt.Sprintf("There is some error occured while creating the new address %v", err), rc)
 			return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
 		}
+		if d, _ := io.IOData.Get(appconstant.IO_DUPLICATE_OF); d != nil {
+			if duplicate, ok := d.(*AddressResponse); ok && duplicate != nil {
+				addressResult.DuplicateOf = duplicate.Id
+			}
+		}
 	}
 	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, addressResult)
 	if derr != nil {
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type UpdatePreferencesAPI struct {
+}
+
+func (a *UpdatePreferencesAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "PUT",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/preferences",
+	}
+}
+
+func (a *UpdatePreferencesAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("UpdatePreferences")
+}
+
+func (a *UpdatePreferencesAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *UpdatePreferencesAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *UpdatePreferencesAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -2,10 +2,8 @@ package address
 
 import (
 	"common/appconstant"
-	"fmt"
 
 	"github.com/jabong/florest-core/src/common/constants"
-	"github.com/jabong/florest-core/src/common/logger"
 	"github.com/jabong/florest-core/src/common/ratelimiter"
 	"github.com/jabong/florest-core/src/core/common/orchestrator"
 	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
@@ -26,48 +24,7 @@ func (a *UpdateTypeAPI) GetVersion() versionmanager.Version {
 }
 
 func (a *UpdateTypeAPI) GetOrchestrator() orchestrator.Orchestrator {
-	logger.Info("Update Type Pipeline Creation Begin")
-
-	updateTypeOrchestrator := new(orchestrator.Orchestrator)
-	updateTypeWorkflow := new(orchestrator.WorkFlowDefinition)
-	updateTypeWorkflow.Create()
-
-	queryTermEnhancer := new(QueryTermEnhancer)
-	queryTermEnhancer.SetID("1")
-	err := updateTypeWorkflow.AddExecutionNode(queryTermEnhancer)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	queryTermValidator := new(QueryTermValidator)
-	queryTermValidator.SetID("2")
-	err = updateTypeWorkflow.AddExecutionNode(queryTermValidator)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	updateTypeExecutor := new(UpdateTypeExecutor)
-	updateTypeExecutor.SetID("3")
-	err = updateTypeWorkflow.AddExecutionNode(updateTypeExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	err = updateTypeWorkflow.AddConnection(queryTermEnhancer, queryTermValidator)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	err = updateTypeWorkflow.AddConnection(queryTermValidator, updateTypeExecutor)
-	if err != nil {
-		logger.Error(fmt.Sprintln(err))
-	}
-
-	updateTypeWorkflow.SetStartNode(queryTermEnhancer)
-	updateTypeOrchestrator.Create(updateTypeWorkflow)
-	logger.Info(updateTypeOrchestrator.String())
-	logger.Info("Update Type Pipeline Created")
-	return *updateTypeOrchestrator
+	return getOrchestrator("UpdateType")
 }
 
 func (a *UpdateTypeAPI) GetHealthCheck() healthcheck.HCInterface {
@@ -46,6 +46,9 @@ func (n UpdateTypeExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlow
 	debugInfo := new(Debug)
 	_, err := UpdateType(params, debugInfo)
 	addDebugContents(io, debugInfo)
+	if err == errAddressChanged {
+		return io, &constants.AppError{Code: appconstant.PreconditionFailedErrorCode, Message: err.Error()}
+	}
 	if err != nil {
 		logger.Error(fmt.Sprintf("There is some error occured while updating the type %v", err), rc)
 		return io, &constants.AppError{Code: constants.DbErrorCode, Message: err.Error()}
@@ -0,0 +1,40 @@
+package address
+
+import (
+	"common/appconstant"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/ratelimiter"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/utils/healthcheck"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+type VerifyPhoneAPI struct {
+}
+
+func (a *VerifyPhoneAPI) GetVersion() versionmanager.Version {
+	return versionmanager.Version{
+		Resource: "ADDRESS",
+		Version:  "V1",
+		Action:   "POST",
+		BucketID: constants.OrchestratorBucketDefaultValue,
+		Path:     "{" + appconstant.URLPARAM_ADDRESSID + "}/phone/verify",
+	}
+}
+
+func (a *VerifyPhoneAPI) GetOrchestrator() orchestrator.Orchestrator {
+	return getOrchestrator("VerifyPhone")
+}
+
+func (a *VerifyPhoneAPI) GetHealthCheck() healthcheck.HCInterface {
+	return new(AddressHealthCheck)
+}
+
+func (a *VerifyPhoneAPI) Init() {
+	//api initialization should come here
+}
+
+func (a *VerifyPhoneAPI) GetRateLimiter() ratelimiter.RateLimiter {
+	return nil
+}
@@ -0,0 +1,102 @@
+package address
+
+import (
+	"common/appconfig"
+	"common/appconstant"
+	"fmt"
+	"sync"
+
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/core/common/orchestrator"
+)
+
+var (
+	workflowOnce    sync.Once
+	workflowConfigs map[string]*orchestrator.WorkFlowConfig
+	workflowErr     error
+)
+
+//registerWorkflowNodes registers the nodes the workflow definitions refer to by name
+func registerWorkflowNodes() {
+	nodes := map[string]orchestrator.NodeConstructor{
+		"QueryTermEnhancer":          func() orchestrator.WorkFlowNodeInterface { return new(QueryTermEnhancer) },
+		"QueryTermValidator":         func() orchestrator.WorkFlowNodeInterface { return new(QueryTermValidator) },
+		"PreconditionValidator":      func() orchestrator.WorkFlowNodeInterface { return new(PreconditionValidator) },
+		"AddressValidator":           func() orchestrator.WorkFlowNodeInterface { return new(AddressValidator) },
+		"DataEncryptor":              func() orchestrator.WorkFlowNodeInterface { return new(DataEncryptor) },
+		"IdempotencyChecker":         func() orchestrator.WorkFlowNodeInterface { return new(IdempotencyChecker) },
+		"IdempotencyReplayer":        func() orchestrator.WorkFlowNodeInterface { return new(IdempotencyReplayer) },
+		"IdempotencyRecorder":        func() orchestrator.WorkFlowNodeInterface { return new(IdempotencyRecorder) },
+		"DuplicateAddressDetector":   func() orchestrator.WorkFlowNodeInterface { return new(DuplicateAddressDetector) },
+		"DuplicateAddressResponder":  func() orchestrator.WorkFlowNodeInterface { return new(DuplicateAddressResponder) },
+		"ListAddressExecutor":        func() orchestrator.WorkFlowNodeInterface { return new(ListAddressExecutor) },
+		"AddressFetchFork":           func() orchestrator.WorkFlowNodeInterface { return new(AddressFetchFork) },
+		"RegionMetadataLoader":       func() orchestrator.WorkFlowNodeInterface { return new(RegionMetadataLoader) },
+		"AddressJoiner":              func() orchestrator.WorkFlowNodeInterface { return new(AddressJoiner) },
+		"UpdateAddressExecutor":      func() orchestrator.WorkFlowNodeInterface { return new(UpdateAddressExecutor) },
+		"PatchAddressExecutor":       func() orchestrator.WorkFlowNodeInterface { return new(PatchAddressExecutor) },
+		"DeleteAddressExecutor":      func() orchestrator.WorkFlowNodeInterface { return new(DeleteAddressExecutor) },
+		"UpdateTypeExecutor":         func() orchestrator.WorkFlowNodeInterface { return new(UpdateTypeExecutor) },
+		"AddressHistoryExecutor":     func() orchestrator.WorkFlowNodeInterface { return new(AddressHistoryExecutor) },
+		"RestoreAddressExecutor":     func() orchestrator.WorkFlowNodeInterface { return new(RestoreAddressExecutor) },
+		"PreferencesValidator":       func() orchestrator.WorkFlowNodeInterface { return new(PreferencesValidator) },
+		"AddressPreferencesExecutor": func() orchestrator.WorkFlowNodeInterface { return new(AddressPreferencesExecutor) },
+		"SendOtpExecutor":            func() orchestrator.WorkFlowNodeInterface { return new(SendOtpExecutor) },
+		"OtpValidator":               func() orchestrator.WorkFlowNodeInterface { return new(OtpValidator) },
+		"ConfirmOtpExecutor":         func() orchestrator.WorkFlowNodeInterface { return new(ConfirmOtpExecutor) },
+		"AdminAuthenticator":         func() orchestrator.WorkFlowNodeInterface { return new(AdminAuthenticator) },
+		"AdminAddressExecutor":       func() orchestrator.WorkFlowNodeInterface { return new(AdminAddressExecutor) },
+		"WorkflowGraphExecutor":      func() orchestrator.WorkFlowNodeInterface { return new(WorkflowGraphExecutor) },
+	}
+	for name, constructor := range nodes {
+		orchestrator.RegisterNode(name, constructor)
+	}
+}
+
+//loadWorkflowConfigs reads the workflow definitions and validates all of them, used by an API or not
+func loadWorkflowConfigs() {
+	registerWorkflowNodes()
+	fileName := appconstant.DEFAULT_WORKFLOW_CONFIG
+	if appConfig, err := appconfig.GetAddressServiceConfig(); err == nil && appConfig.Workflows != "" {
+		fileName = appConfig.Workflows
+	}
+	workflowConfigs, workflowErr = orchestrator.LoadWorkFlowConfigs(fileName)
+	if workflowErr != nil {
+		return
+	}
+	names := make(map[string]bool)
+	for name, conf := range workflowConfigs {
+		if err := new(orchestrator.Orchestrator).CreateFromWorkFlowConfig(conf); err != nil {
+			workflowErr = fmt.Errorf("Invalid workflow %s in %s - %v", name, fileName, err)
+			return
+		}
+		names[name] = true
+	}
+	workflowErr = validateExperiments(names)
+}
+
+//getOrchestrator creates the orchestrator of a workflow definition, routed to the workflows of the
+//experiment variants replacing it. The service does not start with a missing or invalid workflow
+func getOrchestrator(name string) orchestrator.Orchestrator {
+	logger.Info(name + " Pipeline Creation begin")
+	o := getExperimentOrchestrator(name, createOrchestrator(name))
+	logger.Info(o.String())
+	logger.Info(name + " Pipeline Created")
+	return o
+}
+
+func createOrchestrator(name string) orchestrator.Orchestrator {
+	workflowOnce.Do(loadWorkflowConfigs)
+	if workflowErr != nil {
+		panic(workflowErr.Error())
+	}
+	conf, found := workflowConfigs[name]
+	if !found {
+		panic(fmt.Sprintf("Workflow %s is not defined", name))
+	}
+	o := new(orchestrator.Orchestrator)
+	if err := o.CreateFromWorkFlowConfig(conf); err != nil {
+		panic(fmt.Sprintf("Invalid workflow %s - %v", name, err))
+	}
+	return *o
+}
@@ -0,0 +1,83 @@
+package address
+
+import (
+	"common/appconstant"
+	"fmt"
+
+	"github.com/jabong/florest-core/src/common/constants"
+	"github.com/jabong/florest-core/src/common/logger"
+	"github.com/jabong/florest-core/src/common/profiler"
+	workflow "github.com/jabong/florest-core/src/core/common/orchestrator"
+	"github.com/jabong/florest-core/src/core/common/versionmanager"
+)
+
+//WorkflowGraph is an API registered in the version manager with the graph of its workflow
+//in the DOT and Mermaid languages. APIs without a workflow, like the health check, have no graph
+type WorkflowGraph struct {
+	Resource string `json:"resource"`
+	Version  string `json:"version"`
+	Action   string `json:"action"`
+	Bucket   string `json:"bucket"`
+	Path     string `json:"path"`
+	Dot      string `json:"dot,omitempty"`
+	Mermaid  string `json:"mermaid,omitempty"`
+}
+
+//WorkflowGraphExecutor lists the registered APIs with the executors every request goes through
+type WorkflowGraphExecutor struct {
+	id string
+}
+
+func (n *WorkflowGraphExecutor) SetID(id string) {
+	n.id = id
+}
+
+func (n WorkflowGraphExecutor) GetID() (id string, err error) {
+	return n.id, nil
+}
+
+func (n WorkflowGraphExecutor) Name() string {
+	return "WorkflowGraphExecutor"
+}
+
+func (n WorkflowGraphExecutor) Execute(io workflow.WorkFlowData) (workflow.WorkFlowData, error) {
+	prof := profiler.NewProfiler()
+	prof.StartProfile("WorkflowGraphExecutor")
+
+	defer func() {
+		prof.EndProfileWithMetric([]string{"WorkflowGraphExecutor_execute"})
+	}()
+
+	rc, _ := io.ExecContext.Get(constants.RequestContext)
+	io.ExecContext.SetDebugMsg("Workflow Graph Executor", "Workflow Graph Executor-Execute")
+	graphs, err := getWorkflowGraphs()
+	if err != nil {
+		logger.Error(fmt.Sprintf("WorkflowGraphExecutor: could not list the APIs - %v", err), rc)
+		return io, &constants.AppError{Code: constants.ResourceErrorCode, Message: err.Error()}
+	}
+	derr := io.IOData.Set(appconstant.IO_ADDRESS_RESULT, graphs)
+	if derr != nil {
+		logger.Error(fmt.Sprintf("error in setting workflow graphs to workflow data- %v", derr), rc)
+		return io, &constants.AppError{Code: constants.IncorrectDataErrorCode, Message: derr.Error()}
+	}
+	return io, nil
+}
+
+//getWorkflowGraphs renders the workflow of every API in the version manager
+func getWorkflowGraphs() ([]*WorkflowGraph, error) {
+	list, err := versionmanager.List()
+	if err != nil {
+		return nil, err
+	}
+	graphs := make([]*WorkflowGraph, 0, len(list))
+	for _, registered := range list {
+		ver := registered.Version
+		graph := &WorkflowGraph{Resource: ver.Resource, Version: ver.Version, Action: ver.Action, Bucket: ver.BucketID, Path: ver.Path}
+		if o, ok := registered.Versionable.GetInstance().(workflow.Orchestrator); ok {
+			graph.Dot = o.DOT()
+			graph.Mermaid = o.Mermaid()
+		}
+		graphs = append(graphs, graph)
+	}
+	return graphs, nil
+}
@@ -12,9 +12,18 @@ import (
 )
 
 type AddressServiceConfig struct {
-	MySqlConfig             *MySqlConfig             `json:"MySql",omitempty`
-	EncryptionServiceConfig *EncryptionServiceConfig `json:"EncryptionService,omitempty"`
-	Cache                   *CacheConf               `json:"Cache,omitempty"`
+	MySqlConfig             *MySqlConfig              `json:"MySql",omitempty`
+	EncryptionServiceConfig *EncryptionServiceConfig  `json:"EncryptionService,omitempty"`
+	Cache                   *CacheConf                `json:"Cache,omitempty"`
+	Admin                   *AdminConfig              `json:"Admin,omitempty"`
+	SoftDelete              *SoftDeleteConfig         `json:"SoftDelete,omitempty"`
+	DuplicateDetection      *DuplicateDetectionConfig `json:"DuplicateDetection,omitempty"`
+	PhoneVerification       *PhoneVerificationConfig  `json:"PhoneVerification,omitempty"`
+	Workflows               string                    `json:"Workflows,omitempty"`
+	Experiments             []*ExperimentConfig       `json:"Experiments,omitempty"`
+	Tunables                *TunablesConfig           `json:"Tunables,omitempty"`
+	Debug                   *DebugConfig              `json:"Debug,omitempty"`
+	Metrics                 *MetricsConfig            `json:"Metrics,omitempty"`
 }
 
 type MySqlConfig struct {
@@ -34,6 +43,83 @@ type CacheConf struct {
 	RedisCluster *cache.Config `json:"RedisCluster,omitempty"`
 }
 
+//AdminConfig holds the credentials of the support agents allowed to use the admin APIs
+type AdminConfig struct {
+	Role   string
+	Agents []*AdminAgent
+	//AgentTokens are the tokens of the agents as comma separated agentId:token pairs. They are set from the
+	//environment only, an agent without a token is refused
+	AgentTokens string
+}
+
+type AdminAgent struct {
+	AgentId string
+	Roles   []string
+}
+
+//SoftDeleteConfig controls how long deleted addresses can be restored and how often they are purged
+type SoftDeleteConfig struct {
+	RestoreWindowHours   int
+	PurgeIntervalMinutes int
+}
+
+//DuplicateDetectionConfig controls what happens when a new address matches an existing address of the user.
+//Mode is one of off, return (the existing address is returned) or flag (the address is created and flagged)
+type DuplicateDetectionConfig struct {
+	Mode string
+}
+
+//PhoneVerificationConfig controls the codes sent to verify the phone of an address. Sender names the
+//registered SMS sender, log only writes the message to the log
+type PhoneVerificationConfig struct {
+	CodeLength            int
+	ExpirySeconds         int
+	MaxAttempts           int
+	ResendIntervalSeconds int
+	Sender                string
+}
+
+//MetricsConfig controls the tags of the address metrics. AppIds are the apps tagged with their id, the other
+//apps are tagged as other so that the number of series stays bounded
+type MetricsConfig struct {
+	AppIds []string
+}
+
+//ExperimentConfig is an experiment run on the requests of a bucket. A request is in the variant named by the
+//bucket header (bucket: <Name>:<variant>), the variant replaces workflows by name and sets parameters of the nodes
+type ExperimentConfig struct {
+	Name     string
+	Variants map[string]*ExperimentVariant
+}
+
+type ExperimentVariant struct {
+	Workflows map[string]string
+	Params    map[string]string
+}
+
+//TunablesConfig holds the limits and the validation heuristics of the address service, reloaded at runtime from
+//the dynamic config. What is not configured keeps its default, configured abbreviations and filler words replace
+//the default ones
+type TunablesConfig struct {
+	DefaultLimit          int
+	MaxLimit              int
+	DecryptBatchSize      int
+	PhoneLength           int
+	PostcodeLength        int
+	MaxLabelLength        int
+	MaxComponentLength    int
+	MaxInstructionsLength int
+	MaxDeliveryWindows    int
+	AddressAbbreviations  map[string]string
+	AddressFillerWords    []string
+}
+
+//DebugConfig holds the token a request has to send in X-Jabong-Debug-Token to get the debug data, no request
+//gets it without a token
+type DebugConfig struct {
+	Token string
+}
+
 func GetAddressServiceConfig() (*AddressServiceConfig, error) {
 	c := config.GlobalAppConfig.ApplicationConfig
 	appConfig, ok := c.(*AddressServiceConfig)
@@ -72,6 +158,14 @@ func MapEnvVariables() map[string]string {
 	overrideVar["ApplicationConfig.Cache.Redis.ConnStr"] = "REDIS_CONN_STR"
 	overrideVar["ApplicationConfig.Cache.Redis.Cluster"] = "IS_CLUSTER"
 
+	overrideVar["ApplicationConfig.SoftDelete.RestoreWindowHours"] = "SOFT_DELETE_RESTORE_WINDOW_HOURS"
+	overrideVar["ApplicationConfig.SoftDelete.PurgeIntervalMinutes"] = "SOFT_DELETE_PURGE_INTERVAL_MINUTES"
+	overrideVar["ApplicationConfig.DuplicateDetection.Mode"] = "DUPLICATE_DETECTION_MODE"
+	overrideVar["ApplicationConfig.PhoneVerification.Sender"] = "PHONE_VERIFICATION_SENDER"
+	overrideVar["ApplicationConfig.Workflows"] = "WORKFLOW_CONFIG"
+	overrideVar["ApplicationConfig.Debug.Token"] = "DEBUG_TOKEN"
+	overrideVar["ApplicationConfig.Admin.AgentTokens"] = "ADMIN_AGENT_TOKENS"
+
 	checkEnv(overrideVar)
 	return overrideVar
 }
@@ -7,12 +7,20 @@ const (
 
 //URL Params for address service
 const (
-	SESSION_ID        = "X-Jabong-SessionId"
-	USER_ID           = "X-Jabong-UserId"
-	IO_QUERY          = "QUERY"
-	IO_ADDRESS_RESULT = "RESULT"
-	IO_HTTP_REQUEST   = "REQUEST"
-	IO_REQUEST_PARAMS = "QUERYPARAMS"
+	SESSION_ID         = "X-Jabong-SessionId"
+	USER_ID            = "X-Jabong-UserId"
+	IO_QUERY           = "QUERY"
+	IO_ADDRESS_RESULT  = "RESULT"
+	IO_HTTP_REQUEST    = "REQUEST"
+	IO_REQUEST_PARAMS  = "QUERYPARAMS"
+	IO_REGION_METADATA = "REGIONMETADATA"
+	AGENT_ID           = "X-Jabong-AgentId"
+	ADMIN_REASON       = "X-Jabong-Reason"
+	IDEMPOTENCY_KEY    = "Idempotency-Key"
+	IF_MATCH           = "If-Match"
+	IF_NONE_MATCH      = "If-None-Match"
+	ETAG               = "ETag"
+	DEBUG_TOKEN        = "X-Jabong-Debug-Token"
 )
 
 const (
@@ -22,18 +30,92 @@ const (
 	URLPARAM_POSTCODE    = "postcode"
 	URLPARAM_ADDRESSTYPE = "addressType"
 	URLPARAM_DEFAULT     = "default"
+	URLPARAM_CUSTOMERID  = "customerId"
+	URLPARAM_CITY        = "city"
+	URLPARAM_REGION      = "region"
+	URLPARAM_COUNTRY     = "country"
+	URLPARAM_ISOFFICE    = "address_type"
+	URLPARAM_QUERY       = "q"
+	URLPARAM_LABELTYPE   = "label_type"
+	URLPARAM_INCLUDE     = "include"
+	URLPARAM_REASSIGN    = "reassign"
+	URLPARAM_SUCCESSOR   = "successor"
 )
 
 const (
 	MYSQL_ERROR string = "MysqlError"
 )
 
+//Admin audit actions
+const (
+	ADMIN_ACTION_LIST   = "list"
+	ADMIN_ACTION_VIEW   = "view"
+	ADMIN_ACTION_UPDATE = "update"
+	ADMIN_ACTION_DELETE = "delete"
+)
+
+//Address history actions and actors
+const (
+	HISTORY_ACTION_CREATE      = "create"
+	HISTORY_ACTION_UPDATE      = "update"
+	HISTORY_ACTION_UPDATE_TYPE = "update_type"
+	HISTORY_ACTION_DELETE      = "delete"
+	HISTORY_ACTION_RESTORE     = "restore"
+	HISTORY_ACTOR_CUSTOMER     = "customer"
+	HISTORY_ACTOR_AGENT        = "agent"
+)
+
+//Soft delete defaults, used when not configured
+const (
+	DEFAULT_RESTORE_WINDOW_HOURS   = 72
+	DEFAULT_PURGE_INTERVAL_MINUTES = 60
+	PURGE_BATCH_SIZE               = 500
+)
+
+//Duplicate detection modes
+const (
+	DUPLICATE_MODE_OFF    = "off"
+	DUPLICATE_MODE_RETURN = "return"
+	DUPLICATE_MODE_FLAG   = "flag"
+	IO_DUPLICATE_OF       = "DUPLICATEOF"
+)
+
+//Phone verification defaults, used when not configured
+const (
+	DEFAULT_OTP_LENGTH         = 6
+	DEFAULT_OTP_EXPIRY_SECONDS = 300
+	DEFAULT_OTP_MAX_ATTEMPTS   = 5
+	DEFAULT_OTP_RESEND_SECONDS = 30
+	SMS_SENDER_LOG             = "log"
+	OTP_CODE                   = "code"
+	OTP_MESSAGE                = "%s is your code to verify the phone of your delivery address. It expires in %d minutes."
+	OTP_CACHE_KEY              = "address_otp_%s_%s"
+	OTP_ATTEMPT_CACHE_KEY      = "address_otp_attempt_%s_%s_%d"
+	OTP_RESEND_CACHE_KEY       = "address_otp_resend_%s_%s"
+)
+
+//REGION_METADATA_TTL is how long, in seconds, the regions are kept in memory before they are read again
+const REGION_METADATA_TTL = 10 * 60
+
+//DEFAULT_WORKFLOW_CONFIG is the file of the workflow definitions when not configured, next to conf.json
+const DEFAULT_WORKFLOW_CONFIG = "conf/workflows.json"
+
 //Redis constants
 const (
 	ADDRESS_CACHE_KEY string = "address_list_key_%s"
 	ORDER_CACHE_KEY   string = "order_list_key_%s"
 )
 
+//Idempotency constants
+const (
+	IO_IDEMPOTENCY_RECORD        = "IDEMPOTENCYRECORD"
+	IDEMPOTENCY_CACHE_KEY        = "idempotency_key_%s_%s"
+	IDEMPOTENCY_KEY_TTL          = int32(24 * 60 * 60)
+	IDEMPOTENCY_LOCK_TTL         = int32(30)
+	IDEMPOTENCY_STATUS_PENDING   = "pending"
+	IDEMPOTENCY_STATUS_COMPLETED = "completed"
+)
+
 //Encryption service end points
 const (
 	ENCRYPT_ENDPOINT = "/encryption/v1/encrypt/"
@@ -50,6 +132,8 @@ const (
 	DEFAULT_LIMIT  = 10
 	DEFAULT_OFFSET = 0
 	MAX_LIMIT      = 50
+	HOME           = "home"
+	OFFICE         = "office"
 )
 
 const (
@@ -66,4 +150,81 @@ const (
 	POSTCODE        = "PostCode"
 	COUNTRY         = "Country"
 	SMS_OPT         = "Sms_opt"
+	LABEL           = "Label"
+	LABEL_TYPE      = "LabelType"
+	HOUSE           = "House"
+	BUILDING        = "Building"
+	STREET          = "Street"
+	LOCALITY        = "Locality"
+	LANDMARK        = "Landmark"
+	RECEIVER_NAME   = "ReceiverName"
+	RECEIVER_PHONE  = "ReceiverPhone"
+)
+
+//Validation defaults, used when not configured
+const (
+	PHONE_LENGTH    = 10
+	POSTCODE_LENGTH = 6
+)
+
+//Structured address components
+const (
+	MAX_COMPONENT_LENGTH = 100
+	LANDMARK_PREFIX      = "Near "
+)
+
+//Address label types, work addresses are the ones flagged as office
+const (
+	LABEL_TYPE_HOME  = "home"
+	LABEL_TYPE_WORK  = "work"
+	LABEL_TYPE_OTHER = "other"
+	MAX_LABEL_LENGTH = 64
+)
+
+//Delivery preferences of an address
+const (
+	INCLUDE_PREFERENCES     = "preferences"
+	MAX_INSTRUCTIONS_LENGTH = 255
+	MAX_DELIVERY_WINDOWS    = 3
+	DELIVERY_WINDOW_FORMAT  = "15:04"
+)
+
+//Parameters an experiment variant can set, and their values
+const (
+	EXPERIMENT_PARAM_VALIDATION = "Validation"
+	VALIDATION_STRICT           = "strict"
+	VALIDATION_LENIENT          = "lenient"
+	//PHONE_COUNTRY_CODE is dropped from a phone under lenient validation
+	PHONE_COUNTRY_CODE = "91"
+	//EXPERIMENT_EXPOSURE_METRIC counts the requests exposed to an experiment, tagged by experiment and variant
+	EXPERIMENT_EXPOSURE_METRIC = "experiment_exposure"
+)
+
+//Address metrics, tagged by API version, app id and buckets of the request
+const (
+	//CACHE_LOOKUP_METRIC counts the reads of an address list from cache, tagged by result hit or miss
+	CACHE_LOOKUP_METRIC = "address_cache_lookup"
+	//DB_FALLBACK_METRIC counts the reads from DB when the address list is not in cache, tagged by operation
+	DB_FALLBACK_METRIC = "address_db_fallback"
+	//DECRYPTION_FAILURE_METRIC counts the calls to the encryption service that failed to decrypt
+	DECRYPTION_FAILURE_METRIC = "address_decryption_failure"
+	//DECRYPTION_PARTIAL_METRIC counts the decryptions in batches where some of the batches failed
+	DECRYPTION_PARTIAL_METRIC = "address_decryption_partial"
+	//VALIDATION_FAILURE_METRIC counts the rejected requests, tagged by field and rule
+	VALIDATION_FAILURE_METRIC = "address_validation_failure"
+	//VALIDATION_FLAG_METRIC counts the addresses written, tagged by their validation_flag
+	VALIDATION_FLAG_METRIC = "address_validation_flag"
+	//DEFAULT_REASSIGNMENT_METRIC counts the defaults moved to a successor when a default address is deleted
+	DEFAULT_REASSIGNMENT_METRIC = "address_default_reassignment"
+)
+
+//Rules a field of an address can fail validation on
+const (
+	RULE_JSON     = "json"
+	RULE_TYPE     = "type"
+	RULE_LENGTH   = "length"
+	RULE_VALUE    = "value"
+	RULE_NULL     = "null"
+	RULE_REQUIRED = "required"
+	RULE_TOGETHER = "together"
 )
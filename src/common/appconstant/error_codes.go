@@ -7,13 +7,32 @@ import (
 const (
 	InconsistantDataStateErrorCode       florest_Constant.APPErrorCode = 1407
 	FunctionalityNotImplementedErrorCode florest_Constant.APPErrorCode = 1408
+	AdminUnauthorizedErrorCode           florest_Constant.APPErrorCode = 1409
+	AdminForbiddenErrorCode              florest_Constant.APPErrorCode = 1410
+	AddressNotRestorableErrorCode        florest_Constant.APPErrorCode = 1411
+	IdempotencyConflictErrorCode         florest_Constant.APPErrorCode = 1412
+	PreconditionFailedErrorCode          florest_Constant.APPErrorCode = 1413
+	OtpTooManyRequestsErrorCode          florest_Constant.APPErrorCode = 1414
 )
 
 const (
 	HttpStatusNotImplementedErrorCode florest_Constant.HTTPCode = 501
+	HttpStatusUnauthorizedErrorCode   florest_Constant.HTTPCode = 401
+	HttpStatusForbiddenErrorCode      florest_Constant.HTTPCode = 403
+	HttpStatusGoneErrorCode           florest_Constant.HTTPCode = 410
+	HttpStatusConflictErrorCode       florest_Constant.HTTPCode = 409
+	HttpStatusPreconditionFailedCode  florest_Constant.HTTPCode = 412
+	HttpStatusNotModifiedCode         florest_Constant.HTTPCode = 304
+	HttpStatusTooManyRequestsCode     florest_Constant.HTTPCode = 429
 )
 
 var APPErrorCodeToHTTPCodeMap = map[florest_Constant.APPErrorCode]florest_Constant.HTTPCode{
 	InconsistantDataStateErrorCode:       florest_Constant.HTTPStatusInternalServerErrorCode,
 	FunctionalityNotImplementedErrorCode: HttpStatusNotImplementedErrorCode,
+	AdminUnauthorizedErrorCode:           HttpStatusUnauthorizedErrorCode,
+	AdminForbiddenErrorCode:              HttpStatusForbiddenErrorCode,
+	AddressNotRestorableErrorCode:        HttpStatusGoneErrorCode,
+	IdempotencyConflictErrorCode:         HttpStatusConflictErrorCode,
+	PreconditionFailedErrorCode:          HttpStatusPreconditionFailedCode,
+	OtpTooManyRequestsErrorCode:          HttpStatusTooManyRequestsCode,
 }